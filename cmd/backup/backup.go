@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/darwinfroese/scribe/cmd"
+	"github.com/darwinfroese/scribe/internal/config"
+	"github.com/darwinfroese/scribe/internal/database"
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+// List prints every snapshot kept for the database, oldest first
+func List(args cmd.Args, cfg *config.Config) {
+	store := task.OpenStore(cfg.Database.Backend, args.Global)
+	defer store.Close()
+
+	backups := openBackups(store, cfg)
+
+	snapshots, err := backups.List()
+	if err != nil {
+		log.Fatal("unable to list the database backups: ", err)
+	}
+
+	cmd.PrintHeader(fmt.Sprintf("Backups Of %s", store.Path()))
+
+	if len(snapshots) == 0 {
+		fmt.Println("\tno backups have been taken yet")
+		return
+	}
+
+	for _, snapshot := range snapshots {
+		fmt.Printf("\t%s\t%s\t%d bytes\n", snapshot.Name, snapshot.CreatedAt.Format(time.DateTime), snapshot.Size)
+	}
+}
+
+// Restore replaces the database with the snapshot named in args, the current
+// database is backed up first so that the restore can be undone.
+func Restore(args cmd.Args, cfg *config.Config) {
+	if args.Snapshot == "" {
+		log.Fatal("a snapshot to restore is required, use 'scribe backup list' to see the available snapshots")
+	}
+
+	store := task.OpenStore(cfg.Database.Backend, args.Global)
+	defer store.Close()
+
+	backups := openBackups(store, cfg)
+
+	snapshot, err := backups.Find(args.Snapshot)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Fatalf(`no snapshot named "%s" was found, use 'scribe backup list' to see the available snapshots`, args.Snapshot)
+	}
+
+	if err != nil {
+		log.Fatal("unable to find the snapshot: ", err)
+	}
+
+	current := backups.NextPath()
+
+	err = store.Backup(current)
+	if err != nil {
+		log.Fatal("unable to back up the database before restoring: ", err)
+	}
+
+	err = backups.Prune()
+	if err != nil {
+		log.Fatal("unable to remove old database backups: ", err)
+	}
+
+	err = store.Restore(snapshot.Path)
+	if err != nil {
+		log.Fatal("unable to restore the snapshot: ", err)
+	}
+
+	fmt.Printf("restored %s from %s, the previous database was backed up to %s\n", store.Path(), snapshot.Name, current)
+}
+
+func openBackups(store task.Store, cfg *config.Config) *database.Backups {
+	// restoring always takes a backup, even if automatic backups are off
+	backups, err := database.NewBackups(store.Path(), max(cfg.Backup.Count, 1))
+	if err != nil {
+		log.Fatal("unable to open the backups folder: ", err)
+	}
+
+	return backups
+}
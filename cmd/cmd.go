@@ -1,5 +1,18 @@
 package cmd
 
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/darwinfroese/scribe/internal/config"
+	"github.com/darwinfroese/scribe/internal/database"
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
 type Args struct {
 	Global bool
 	Last   int
@@ -7,4 +20,57 @@ type Args struct {
 	End    string
 	All    bool
 	List   bool
+	Tag    string
+
+	History    bool
+	ByPriority bool
+	Format     string
+	Template   string
+	JSON       bool
+	Period     string
+
+	Snapshot string
+
+	Action  string
+	TaskIDs []int
+	Name    string
+
+	Query string
+}
+
+func PrintHeader(header string) {
+	FprintHeader(os.Stdout, header)
+}
+
+// FprintHeader writes a header underlined with dashes to w
+func FprintHeader(w io.Writer, header string) {
+	length := len(header)
+	divider := strings.Repeat("-", length+2)
+
+	fmt.Fprintf(w, " %s \n", header)
+	fmt.Fprintln(w, divider)
+}
+
+// OpenService opens the configured database for a command with backups and
+// the session day set up from the config, changes that can't be written
+// exit with WriteFailed
+func OpenService(args Args, cfg *config.Config) *task.Service {
+	store := task.OpenStore(cfg.Database.Backend, args.Global)
+
+	service := task.NewService(store)
+	service.EnableBackups(cfg.Backup.Count)
+	service.SetDayBoundary(cfg.Session.Rollover, cfg.Session.Location())
+	service.SetWriteErrorHandler(WriteFailed)
+
+	return service
+}
+
+// WriteFailed exits when a command's changes can't be written to the
+// database, see task.Service.SetWriteErrorHandler
+func WriteFailed(err error) {
+	if errors.Is(err, database.ErrModified) {
+		log.Fatal("unable to write the database content, another scribe process has changed it. run the command again to use the latest changes")
+	}
+
+	log.Fatal("unable to write the database content: ", err)
 }
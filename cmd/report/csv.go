@@ -0,0 +1,161 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var csvTaskColumns = []string{
+	"task_id", "description", "priority", "tags", "due_date", "completed_on", "parent_id", "path",
+	"depth", "subtasks", "subtasks_completed", "notes", "tracked_seconds", "focus_blocks", "carried_over", "history",
+}
+
+var csvHeader = slices.Concat([]string{
+	"session_id", "session", "session_date", "session_name", "session_started_at", "session_ended_at", "session_note",
+	"section",
+}, csvTaskColumns)
+
+var csvRollupHeader = slices.Concat([]string{
+	"period_start", "period_end", "section", "session", "session_note",
+}, csvTaskColumns, []string{"times_planned"})
+
+// csvWriter writes a row for every task in every session, the session's
+// detail is repeated on each of its rows. sessions without any tasks get a
+// single row with the task columns left empty. rollups get a row for every
+// task and every session note instead.
+type csvWriter struct {
+	out *csv.Writer
+
+	wroteHeader bool
+}
+
+func newCSVWriter(out io.Writer) *csvWriter {
+	return &csvWriter{out: csv.NewWriter(out)}
+}
+
+// the title isn't needed since every row describes its session
+func (w *csvWriter) header(title string) {}
+
+func (w *csvWriter) session(session sessionReport) {
+	w.writeHeader()
+
+	sessionColumns := []string{
+		strconv.Itoa(session.ID), session.Title, session.Date, session.Name, session.StartedAt, session.EndedAt, session.Note,
+	}
+
+	sections := []struct {
+		name  string
+		tasks []taskReport
+	}{
+		{"completed", session.Completed},
+		{"incomplete", session.Incomplete},
+		{"overdue", session.Overdue},
+	}
+
+	rows := 0
+
+	for _, section := range sections {
+		for _, task := range section.tasks {
+			w.write(slices.Concat(sessionColumns, []string{section.name}, taskColumns(task)))
+
+			rows++
+		}
+	}
+
+	if rows == 0 {
+		w.write(slices.Concat(sessionColumns, make([]string, len(csvHeader)-len(sessionColumns))))
+	}
+}
+
+func (w *csvWriter) rollup(rollup rollupReport) {
+	w.write(csvRollupHeader)
+
+	sections := []struct {
+		name  string
+		tasks []taskReport
+	}{
+		{"completed", rollup.Completed},
+		{"unfinished", rollup.Unfinished},
+	}
+
+	for _, section := range sections {
+		for _, task := range section.tasks {
+			w.write(slices.Concat(
+				[]string{rollup.Start, rollup.End, section.name, "", ""},
+				taskColumns(task),
+				[]string{strconv.Itoa(task.TimesPlanned)},
+			))
+		}
+	}
+
+	for _, note := range rollup.Notes {
+		w.write(slices.Concat(
+			[]string{rollup.Start, rollup.End, "note", note.Session, note.Note},
+			make([]string, len(csvTaskColumns)+1),
+		))
+	}
+}
+
+// taskColumns are the values of the csvTaskColumns for a task
+func taskColumns(task taskReport) []string {
+	parent := ""
+	if task.Parent != nil {
+		parent = strconv.Itoa(*task.Parent)
+	}
+
+	subtasks := []string{}
+	for _, id := range task.Subtasks {
+		subtasks = append(subtasks, strconv.Itoa(id))
+	}
+
+	return []string{
+		strconv.Itoa(task.ID),
+		task.Description,
+		task.Priority,
+		strings.Join(task.Tags, " "),
+		task.DueDate,
+		task.CompletedOn,
+		parent,
+		strings.Join(task.Path, " > "),
+		strconv.Itoa(task.Depth),
+		strings.Join(subtasks, " "),
+		strconv.Itoa(task.SubtasksCompleted),
+		task.Notes,
+		strconv.Itoa(task.TrackedSeconds),
+		strconv.Itoa(task.FocusBlocks),
+		strconv.Itoa(task.CarriedOver),
+		strings.Join(task.History, "\n"),
+	}
+}
+
+func (w *csvWriter) list(titles []string) {
+	w.write([]string{"session"})
+
+	for _, title := range titles {
+		w.write([]string{title})
+	}
+}
+
+func (w *csvWriter) close() error {
+	w.out.Flush()
+
+	return w.out.Error()
+}
+
+// writeHeader writes the column names before the first session
+func (w *csvWriter) writeHeader() {
+	if w.wroteHeader {
+		return
+	}
+
+	w.write(csvHeader)
+	w.wroteHeader = true
+}
+
+// errors are kept by the csv writer and returned by close
+func (w *csvWriter) write(record []string) {
+	_ = w.out.Write(record)
+}
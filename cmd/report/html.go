@@ -0,0 +1,111 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"tracked": trackedString,
+	"extras":  taskExtras,
+	"tags":    task.TagsString,
+	"path": func(path []string) string {
+		return strings.Join(path, " > ")
+	},
+	"list": func(values ...any) []any {
+		return values
+	},
+	"group": groupTasks,
+	"indent": func(depth int) int {
+		return depth * 2
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ if .Title }}{{ .Title }}{{ else if .Rollup }}{{ .Rollup.Title }}{{ else }}Scribe Report{{ end }}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; }
+.subtext { color: #777; }
+.note { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+{{ with .Title }}<h1>{{ . }}</h1>{{ end }}
+{{ with .Titles }}<ul>
+{{ range . }}<li>{{ . }}</li>
+{{ end }}</ul>{{ end }}
+{{ range .Sessions }}<section>
+<h2>{{ .Title }} ({{ .CompletedCount }}/{{ .PlannedCount }})</h2>
+{{ if .TrackedSeconds }}<p><strong>Time tracked:</strong> {{ tracked .TrackedSeconds }}</p>{{ end }}
+{{ if .FocusBlocks }}<p><strong>Focus blocks:</strong> {{ .FocusBlocks }}</p>{{ end }}
+<h3>Summary</h3>
+{{ if .Note }}<p class="note">{{ .Note }}</p>{{ else }}<p class="subtext">No note.</p>{{ end }}
+{{ template "tasks" (list "Completed Tasks" .GroupedBy .Completed) }}
+{{ template "tasks" (list "Incomplete Tasks" .GroupedBy .Incomplete) }}
+{{ if .Overdue }}{{ template "tasks" (list "Overdue When The Session Ended" .GroupedBy .Overdue) }}{{ end }}
+</section>
+{{ end }}
+{{ with .Rollup }}<section>
+<h1>{{ .Title }}</h1>
+<p><strong>Sessions:</strong> {{ .SessionCount }}<br>
+<strong>Tasks planned:</strong> {{ .PlannedCount }}<br>
+<strong>Tasks completed:</strong> {{ .CompletedCount }}
+{{- if .TrackedSeconds }}<br>
+<strong>Time tracked:</strong> {{ tracked .TrackedSeconds }}{{ end }}
+{{- if .FocusBlocks }}<br>
+<strong>Focus blocks:</strong> {{ .FocusBlocks }}{{ end }}</p>
+{{ template "tasks" (list "Completed Tasks" .GroupedBy .Completed) }}
+{{ template "tasks" (list "Planned Repeatedly But Never Finished" .GroupedBy .Unfinished) }}
+<h3>Notes</h3>
+{{ range .Notes }}<h4>{{ .Session }}</h4>
+<p class="note">{{ .Note }}</p>
+{{ else }}<p class="subtext">No notes.</p>
+{{ end }}</section>
+{{ end }}
+</body>
+</html>
+{{ define "tasks" }}<h3>{{ index . 0 }}</h3>
+{{ with index . 2 }}{{ range group (index $ 1) . }}{{ with .Key }}<h4>{{ . }}</h4>
+{{ end }}<ul>
+{{ range .Tasks }}<li{{ with .Depth }} style="margin-left: {{ indent . }}em"{{ end }}>{{ if .CompletedOn }}&#10003;{{ else }}&#9675;{{ end }} {{ .Description }} <strong>({{ .Priority }})</strong>
+{{- with .Tags }} <span class="subtext">{{ tags . }}</span>{{ end }}
+{{- if not .Depth }}{{ with .Path }} <span class="subtext">under {{ path . }}</span>{{ end }}{{ end }}
+{{- with .DueDate }} <span class="subtext">due {{ . }}</span>{{ end }}
+{{- with .CompletedOn }} <span class="subtext">completed {{ . }}</span>{{ end }}
+{{- with extras . }} <span class="subtext">{{ . }}</span>{{ end }}
+{{- with .Notes }}<p class="note">{{ . }}</p>{{ end }}
+{{- with .History }}<ul class="subtext">{{ range . }}<li>{{ . }}</li>{{ end }}</ul>{{ end }}</li>
+{{ end }}</ul>
+{{ end }}{{ else }}<p class="subtext">None.</p>{{ end }}
+{{ end }}`))
+
+// htmlWriter writes the whole report as a standalone HTML page
+type htmlWriter struct {
+	out io.Writer
+
+	report reportData
+}
+
+func (w *htmlWriter) header(title string) {
+	w.report.Title = title
+}
+
+func (w *htmlWriter) session(session sessionReport) {
+	w.report.Sessions = append(w.report.Sessions, session)
+}
+
+func (w *htmlWriter) list(titles []string) {
+	w.report.Titles = append(w.report.Titles, titles...)
+}
+
+func (w *htmlWriter) rollup(rollup rollupReport) {
+	w.report.Rollup = &rollup
+}
+
+func (w *htmlWriter) close() error {
+	return htmlTemplate.Execute(w.out, w.report)
+}
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonWriter writes the whole report as a single JSON document for scripts
+type jsonWriter struct {
+	out io.Writer
+
+	report reportData
+}
+
+func (w *jsonWriter) header(title string) {
+	w.report.Title = title
+}
+
+func (w *jsonWriter) session(session sessionReport) {
+	w.report.Sessions = append(w.report.Sessions, session)
+}
+
+func (w *jsonWriter) list(titles []string) {
+	w.report.Titles = append(w.report.Titles, titles...)
+}
+
+func (w *jsonWriter) rollup(rollup rollupReport) {
+	w.report.Rollup = &rollup
+}
+
+func (w *jsonWriter) close() error {
+	if w.report.Sessions == nil {
+		w.report.Sessions = []sessionReport{}
+	}
+
+	encoder := json.NewEncoder(w.out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(w.report)
+}
@@ -0,0 +1,167 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+// markdownWriter writes reports that can be pasted into pull requests or a
+// wiki
+type markdownWriter struct {
+	out io.Writer
+}
+
+func (w *markdownWriter) header(title string) {
+	fmt.Fprintf(w.out, "# %s\n\n", markdownEscaper.Replace(title))
+}
+
+func (w *markdownWriter) session(session sessionReport) {
+	fmt.Fprintf(w.out, "## %s (%d/%d)\n\n", markdownEscaper.Replace(session.Title), session.CompletedCount, session.PlannedCount)
+
+	if session.TrackedSeconds > 0 {
+		fmt.Fprintf(w.out, "**Time tracked:** %s  \n", trackedString(session.TrackedSeconds))
+	}
+
+	if session.FocusBlocks > 0 {
+		fmt.Fprintf(w.out, "**Focus blocks:** %d  \n", session.FocusBlocks)
+	}
+
+	if session.TrackedSeconds > 0 || session.FocusBlocks > 0 {
+		fmt.Fprintln(w.out)
+	}
+
+	fmt.Fprintf(w.out, "### Summary\n\n")
+
+	if session.Note == "" {
+		fmt.Fprintf(w.out, "_No note._\n\n")
+	} else {
+		// the note is written as is, notes are often markdown already
+		fmt.Fprintf(w.out, "%s\n\n", strings.TrimSpace(session.Note))
+	}
+
+	w.tasks("Completed Tasks", session.GroupedBy, session.Completed)
+	w.tasks("Incomplete Tasks", session.GroupedBy, session.Incomplete)
+
+	if len(session.Overdue) > 0 {
+		w.tasks("Overdue When The Session Ended", session.GroupedBy, session.Overdue)
+	}
+}
+
+func (w *markdownWriter) tasks(header, groupedBy string, tasks []taskReport) {
+	fmt.Fprintf(w.out, "### %s\n\n", header)
+
+	if len(tasks) == 0 {
+		fmt.Fprintf(w.out, "_None._\n\n")
+		return
+	}
+
+	groups, err := groupTasks(groupedBy, tasks)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, group := range groups {
+		if group.Key != "" {
+			fmt.Fprintf(w.out, "#### %s\n\n", markdownEscaper.Replace(group.Key))
+		}
+
+		for _, task := range group.Tasks {
+			w.task(task)
+		}
+
+		fmt.Fprintln(w.out)
+	}
+}
+
+// task writes a task as a list item, nested tasks are indented under the
+// task they're nested under
+func (w *markdownWriter) task(item taskReport) {
+	indent := strings.Repeat("  ", item.Depth)
+
+	check := " "
+	if item.CompletedOn != "" {
+		check = "x"
+	}
+
+	fmt.Fprintf(w.out, "%s- [%s] %s **(%s)**", indent, check, markdownEscaper.Replace(item.Description), item.Priority)
+
+	if len(item.Tags) > 0 {
+		fmt.Fprintf(w.out, " `%s`", task.TagsString(item.Tags))
+	}
+
+	if item.Depth == 0 && len(item.Path) > 0 {
+		fmt.Fprintf(w.out, " — under _%s_", markdownEscaper.Replace(strings.Join(item.Path, " > ")))
+	}
+
+	if item.DueDate != "" {
+		fmt.Fprintf(w.out, " — due %s", item.DueDate)
+	}
+
+	if item.CompletedOn != "" {
+		fmt.Fprintf(w.out, " — completed %s", item.CompletedOn)
+	}
+
+	fmt.Fprintf(w.out, "%s\n", taskExtras(item))
+
+	if item.Notes != "" {
+		for _, line := range strings.Split(strings.TrimSpace(item.Notes), "\n") {
+			fmt.Fprintf(w.out, "%s  > %s\n", indent, line)
+		}
+	}
+
+	for _, entry := range item.History {
+		fmt.Fprintf(w.out, "%s  - %s\n", indent, markdownEscaper.Replace(entry))
+	}
+}
+
+func (w *markdownWriter) rollup(rollup rollupReport) {
+	fmt.Fprintf(w.out, "# %s\n\n", markdownEscaper.Replace(rollup.Title))
+
+	fmt.Fprintf(w.out, "**Sessions:** %d  \n", rollup.SessionCount)
+	fmt.Fprintf(w.out, "**Tasks planned:** %d  \n", rollup.PlannedCount)
+	fmt.Fprintf(w.out, "**Tasks completed:** %d  \n", rollup.CompletedCount)
+
+	if rollup.TrackedSeconds > 0 {
+		fmt.Fprintf(w.out, "**Time tracked:** %s  \n", trackedString(rollup.TrackedSeconds))
+	}
+
+	if rollup.FocusBlocks > 0 {
+		fmt.Fprintf(w.out, "**Focus blocks:** %d  \n", rollup.FocusBlocks)
+	}
+
+	fmt.Fprintln(w.out)
+
+	w.tasks("Completed Tasks", rollup.GroupedBy, rollup.Completed)
+	w.tasks("Planned Repeatedly But Never Finished", rollup.GroupedBy, rollup.Unfinished)
+
+	fmt.Fprintf(w.out, "### Notes\n\n")
+
+	if len(rollup.Notes) == 0 {
+		fmt.Fprintf(w.out, "_No notes._\n\n")
+	}
+
+	for _, note := range rollup.Notes {
+		// the notes are written as is, like the session summaries
+		fmt.Fprintf(w.out, "#### %s\n\n%s\n\n", markdownEscaper.Replace(note.Session), strings.TrimSpace(note.Note))
+	}
+}
+
+func (w *markdownWriter) list(titles []string) {
+	for _, title := range titles {
+		fmt.Fprintf(w.out, "- %s\n", markdownEscaper.Replace(title))
+	}
+
+	fmt.Fprintln(w.out)
+}
+
+func (w *markdownWriter) close() error {
+	return nil
+}
@@ -0,0 +1,202 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+// groupByPriority groups a session's tasks by the priority of the task at the
+// top of their tree
+const groupByPriority = "priority"
+
+// reportData is the whole report, a Title followed by either Sessions, the
+// Titles of sessions when they're only being listed or the Rollup of a period
+type reportData struct {
+	Title    string          `json:"title,omitempty"`
+	Sessions []sessionReport `json:"sessions"`
+	Titles   []string        `json:"session_titles,omitempty"`
+	Rollup   *rollupReport   `json:"rollup,omitempty"`
+}
+
+// sessionReport is everything reported about a session, it's what each of
+// the output formats is written from
+type sessionReport struct {
+	task.ReportSession
+
+	TrackedSeconds int `json:"tracked_seconds"`
+	FocusBlocks    int `json:"focus_blocks"`
+
+	// GroupedBy is set to "priority" when the task lists are grouped by
+	// priority, the trees are ordered from critical to low
+	GroupedBy string `json:"grouped_by,omitempty"`
+
+	Completed  []taskReport `json:"completed_tasks"`
+	Incomplete []taskReport `json:"incomplete_tasks"`
+	Overdue    []taskReport `json:"overdue_tasks"`
+}
+
+// taskReport is everything reported about a task planned in a session, the
+// time, focus blocks and carry over count are for that session only. Depth
+// is how far the task is nested under other tasks in the same list.
+// TimesPlanned is only set in rollups, where the time and focus blocks are
+// for the whole period.
+type taskReport struct {
+	task.ReportTask
+
+	Depth          int      `json:"depth"`
+	TrackedSeconds int      `json:"tracked_seconds"`
+	FocusBlocks    int      `json:"focus_blocks"`
+	CarriedOver    int      `json:"carried_over"`
+	TimesPlanned   int      `json:"times_planned,omitempty"`
+	History        []string `json:"history,omitempty"`
+}
+
+func (svc *service) sessionReport(id int) sessionReport {
+	return sessionReport{
+		ReportSession:  svc.tasks.GetReportSession(id),
+		GroupedBy:      svc.groupedBy(),
+		TrackedSeconds: seconds(svc.tasks.SessionTotalTime(id)),
+		FocusBlocks:    svc.tasks.SessionPomodoroTotal(id),
+		Completed:      svc.taskReports(id, svc.filterTasks(svc.tasks.GetCompletedTaskIDsForSession(id))),
+		Incomplete:     svc.taskReports(id, svc.filterTasks(svc.tasks.GetIncompleteTaskIDsForSession(id))),
+		Overdue:        svc.taskReports(id, svc.filterTasks(svc.tasks.GetOverdueTaskIDsForSession(id))),
+	}
+}
+
+func (svc *service) taskReports(sessionID int, ids []int) []taskReport {
+	reports := []taskReport{}
+
+	for _, id := range ids {
+		report := svc.taskReport([]int{sessionID}, id)
+		report.CarriedOver = svc.tasks.CarryOverCount(sessionID, id)
+
+		reports = append(reports, report)
+	}
+
+	return svc.treeOrder(reports)
+}
+
+// taskReport reports on a task over the sessions, the time and focus blocks
+// are added up across all of them
+func (svc *service) taskReport(sessions []int, id int) taskReport {
+	report := taskReport{
+		ReportTask: svc.tasks.GetReportTask(id),
+	}
+
+	for _, session := range sessions {
+		report.TrackedSeconds += seconds(svc.tasks.SessionTrackedTime(session, id))
+		report.FocusBlocks += svc.tasks.SessionPomodoroCount(session, id)
+	}
+
+	if svc.history {
+		report.History = svc.tasks.GetHistory(id)
+	}
+
+	return report
+}
+
+func (svc *service) groupedBy() string {
+	if svc.byPriority {
+		return groupByPriority
+	}
+
+	return ""
+}
+
+// treeOrder orders tasks as trees, each task is followed by the tasks nested
+// under it. a task is nested under its closest ancestor in the list, tasks
+// without one are at the top of a tree. when grouping by priority the trees
+// are ordered by the priority of the task at the top.
+func (svc *service) treeOrder(tasks []taskReport) []taskReport {
+	children := map[int][]taskReport{}
+	roots := []taskReport{}
+
+	for _, task := range tasks {
+		ancestor, ok := svc.closestAncestor(task, tasks)
+		if !ok {
+			roots = append(roots, task)
+			continue
+		}
+
+		children[ancestor] = append(children[ancestor], task)
+	}
+
+	if svc.byPriority {
+		slices.SortStableFunc(roots, func(a, b taskReport) int {
+			return a.PriorityLevel - b.PriorityLevel
+		})
+	}
+
+	ordered := []taskReport{}
+
+	var add func(task taskReport, depth int)
+	add = func(task taskReport, depth int) {
+		task.Depth = depth
+		ordered = append(ordered, task)
+
+		for _, child := range children[task.ID] {
+			add(child, depth+1)
+		}
+	}
+
+	for _, root := range roots {
+		add(root, 0)
+	}
+
+	return ordered
+}
+
+// closestAncestor returns the id of the closest ancestor of task in tasks
+func (svc *service) closestAncestor(task taskReport, tasks []taskReport) (int, bool) {
+	for parent := task.Parent; parent != nil; parent = svc.tasks.GetReportTask(*parent).Parent {
+		if slices.ContainsFunc(tasks, func(task taskReport) bool { return task.ID == *parent }) {
+			return *parent, true
+		}
+	}
+
+	return 0, false
+}
+
+func seconds(duration time.Duration) int {
+	return int(duration.Round(time.Second).Seconds())
+}
+
+// trackedString formats tracked seconds the same way as the task lists
+func trackedString(seconds int) string {
+	return task.DurationString(time.Duration(seconds) * time.Second)
+}
+
+// taskExtras describes the subtask progress of a task and its time, focus
+// blocks, carry overs and the number of times it was planned in the session
+// (or rollup), e.g. " (3/5 subtasks) (0:25:00 tracked)"
+func taskExtras(task taskReport) string {
+	extra := ""
+
+	if len(task.Subtasks) > 0 {
+		extra = fmt.Sprintf(" (%d/%d subtasks)", task.SubtasksCompleted, len(task.Subtasks))
+	}
+
+	if task.TrackedSeconds > 0 {
+		extra = fmt.Sprintf("%s (%s tracked)", extra, trackedString(task.TrackedSeconds))
+	}
+
+	switch {
+	case task.FocusBlocks == 1:
+		extra = fmt.Sprintf("%s (1 focus block)", extra)
+	case task.FocusBlocks > 1:
+		extra = fmt.Sprintf("%s (%d focus blocks)", extra, task.FocusBlocks)
+	}
+
+	if task.CarriedOver > 0 {
+		extra = fmt.Sprintf("%s (carried over %d×)", extra, task.CarriedOver)
+	}
+
+	if task.TimesPlanned > 1 {
+		extra = fmt.Sprintf("%s (planned %d×)", extra, task.TimesPlanned)
+	}
+
+	return extra
+}
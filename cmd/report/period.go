@@ -0,0 +1,93 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	periodWeek  = "week"
+	periodMonth = "month"
+)
+
+// period is the range of dates (inclusive, in YYYY-MM-DD format) that a
+// rollup covers
+type period struct {
+	title string
+	start string
+	end   string
+}
+
+// parsePeriod parses "week" or "month" (the week or month containing today),
+// an ISO week (e.g. "2025-W02"), a month (e.g. "2025-01") or a range of dates
+// (e.g. "2025-01-06..2025-01-17")
+func parsePeriod(value, today string) (period, error) {
+	if start, end, ok := strings.Cut(value, ".."); ok {
+		for _, date := range []string{start, end} {
+			if _, err := time.Parse(time.DateOnly, date); err != nil {
+				return period{}, fmt.Errorf("invalid date %q, dates must be in YYYY-MM-DD format", date)
+			}
+		}
+
+		if end < start {
+			return period{}, fmt.Errorf("the end date %s is before the start date %s", end, start)
+		}
+
+		return period{title: fmt.Sprintf("%s To %s", start, end), start: start, end: end}, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, today)
+	if err != nil {
+		return period{}, err
+	}
+
+	switch value {
+	case periodWeek:
+		year, week := date.ISOWeek()
+		value = fmt.Sprintf("%d-W%02d", year, week)
+	case periodMonth:
+		value = date.Format("2006-01")
+	}
+
+	var year, week int
+	if _, err := fmt.Sscanf(value, "%4d-W%2d", &year, &week); err == nil && len(value) == len("2006-W01") {
+		return weekPeriod(year, week)
+	}
+
+	if month, err := time.Parse("2006-01", value); err == nil {
+		end := month.AddDate(0, 1, -1)
+
+		return period{
+			title: month.Format("January 2006"),
+			start: month.Format(time.DateOnly),
+			end:   end.Format(time.DateOnly),
+		}, nil
+	}
+
+	return period{}, fmt.Errorf("invalid period %q, expected \"week\", \"month\", YYYY-Www, YYYY-MM or YYYY-MM-DD..YYYY-MM-DD", value)
+}
+
+// weekPeriod returns the Monday to Sunday of an ISO week
+func weekPeriod(year, week int) (period, error) {
+	if week < 1 || week > 53 {
+		return period{}, fmt.Errorf("invalid week %d, weeks are numbered 1 to 53", week)
+	}
+
+	// january 4th is always in the first week of the year
+	monday := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday = monday.AddDate(0, 0, -((int(monday.Weekday())+6)%7)+(week-1)*7)
+
+	if _, actual := monday.ISOWeek(); actual != week {
+		return period{}, fmt.Errorf("%d doesn't have a week %d", year, week)
+	}
+
+	start := monday.Format(time.DateOnly)
+	end := monday.AddDate(0, 0, 6).Format(time.DateOnly)
+
+	return period{
+		title: fmt.Sprintf("Week %d-W%02d (%s To %s)", year, week, start, end),
+		start: start,
+		end:   end,
+	}, nil
+}
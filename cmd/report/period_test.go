@@ -0,0 +1,67 @@
+package report
+
+import (
+	"testing"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected period
+		fails    bool
+	}{
+		{
+			name:     "this week",
+			value:    "week",
+			expected: period{title: "Week 2026-W42 (2026-10-12 To 2026-10-18)", start: "2026-10-12", end: "2026-10-18"},
+		},
+		{
+			name:     "this month",
+			value:    "month",
+			expected: period{title: "October 2026", start: "2026-10-01", end: "2026-10-31"},
+		},
+		{
+			name:     "week in the previous year",
+			value:    "2026-W01",
+			expected: period{title: "Week 2026-W01 (2025-12-29 To 2026-01-04)", start: "2025-12-29", end: "2026-01-04"},
+		},
+		{
+			name:     "leap month",
+			value:    "2024-02",
+			expected: period{title: "February 2024", start: "2024-02-01", end: "2024-02-29"},
+		},
+		{
+			name:     "range",
+			value:    "2025-01-06..2025-01-17",
+			expected: period{title: "2025-01-06 To 2025-01-17", start: "2025-01-06", end: "2025-01-17"},
+		},
+		{name: "range ending before it starts", value: "2025-01-17..2025-01-06", fails: true},
+		{name: "invalid date in range", value: "2025-01-06..tomorrow", fails: true},
+		{name: "week out of range", value: "2026-W54", fails: true},
+		{name: "year without a week 53", value: "2025-W53", fails: true},
+		{name: "unknown period", value: "fortnight", fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parsePeriod(test.value, "2026-10-18")
+
+			if test.fails {
+				if err == nil {
+					t.Fatalf("expected %q to be invalid, got %+v", test.value, got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != test.expected {
+				t.Fatalf("expected %+v, got %+v", test.expected, got)
+			}
+		})
+	}
+}
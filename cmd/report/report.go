@@ -2,30 +2,54 @@ package report
 
 import (
 	"fmt"
-	"strings"
+	"log"
+	"os"
 
 	"github.com/darwinfroese/scribe/cmd"
-	"github.com/darwinfroese/scribe/internal/database"
+	"github.com/darwinfroese/scribe/internal/config"
 	"github.com/darwinfroese/scribe/internal/task"
 )
 
 type service struct {
-	db    *database.Database
-	tasks *task.Service
+	tasks      *task.Service
+	out        writer
+	tag        string
+	history    bool
+	byPriority bool
 }
 
-func Report(args cmd.Args) {
-	db := database.New(args.Global)
+func Report(args cmd.Args, cfg *config.Config) {
 	svc := &service{
-		db:    db,
-		tasks: task.NewService(db),
+		tasks:      cmd.OpenService(args, cfg),
+		tag:        args.Tag,
+		history:    args.History,
+		byPriority: args.ByPriority,
 	}
+	defer svc.tasks.Close()
+
+	out, err := newWriter(args.Format, args.Template, cfg.Report, os.Stdout)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	svc.out = out
+	defer func() {
+		if err := svc.out.close(); err != nil {
+			log.Fatal("unable to write the report: ", err)
+		}
+	}()
 
 	if args.List {
 		svc.listAllSessions()
 		return
 	}
 
+	if args.Period != "" {
+		svc.reportPeriod(args.Period)
+		return
+	}
+
 	if args.All {
 		svc.reportAllSessions()
 		return
@@ -50,22 +74,24 @@ func Report(args cmd.Args) {
 }
 
 func (svc *service) listAllSessions() {
-	dates := svc.tasks.GetAllSessionDates()
-
-	printHeader("All Recorded Session Dates")
+	sessions := svc.tasks.GetAllSessionIDs(false)
 
-	for _, date := range dates {
-		fmt.Printf("\t%s\n", date)
+	titles := []string{}
+	for _, session := range sessions {
+		titles = append(titles, svc.tasks.SessionTitle(session))
 	}
+
+	svc.out.header("All Recorded Sessions")
+	svc.out.list(titles)
 }
 
 func (svc *service) reportAllSessions() {
 	sessions := svc.tasks.GetAllSessionIDs(true)
 
-	printHeader("All Sessions")
+	svc.out.header("All Sessions")
 
 	for _, session := range sessions {
-		svc.printSessionDetails(session)
+		svc.out.session(svc.sessionReport(session))
 	}
 }
 
@@ -79,56 +105,36 @@ func (svc *service) reportLastSessions(lastCount int) {
 	filtered := sessions[len(sessions)-lastCount:]
 
 	for _, id := range filtered {
-		svc.printSessionDetails(id)
+		svc.out.session(svc.sessionReport(id))
 	}
 }
 
 func (svc *service) reportDateRangeSessions(start, end string) {
-	sessions := svc.tasks.GetAllSessionIDs(true)
+	sessions, err := svc.tasks.GetSessionIDsBetween(start, end)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	printHeader(fmt.Sprintf("Sessions Between %s And %s", start, end))
+	svc.out.header(fmt.Sprintf("Sessions Between %s And %s", start, end))
 
 	for _, session := range sessions {
-		date := svc.tasks.GetSessionDate(session)
-
-		if date >= start && date <= end {
-			svc.printSessionDetails(session)
-		}
+		svc.out.session(svc.sessionReport(session))
 	}
 }
 
-func printHeader(header string) {
-	length := len(header)
-	divider := strings.Repeat("-", length+2)
-
-	fmt.Printf(" %s \n", header)
-	fmt.Println(divider)
-}
-
-func (svc *service) printSessionDetails(sessionID int) {
-	note := svc.tasks.GetNoteForSession(sessionID)
-	completedTasks := svc.tasks.GetCompletedTaskIDsForSession(sessionID)
-	incompleteTasks := svc.tasks.GetIncompleteTaskIDsForSession(sessionID)
-
-	printHeader(svc.tasks.SessionDisplayStringPlainText(sessionID))
-
-	fmt.Printf("summary: %s\n\n", note)
-	svc.printTasks("completed tasks:", completedTasks, true)
-	svc.printTasks("incomplete tasks:", incompleteTasks, false)
-}
-
-func (svc *service) printTasks(header string, tasks []int, completed bool) {
-	fmt.Println(header)
-
-	prefix := "🡢"
-
-	if completed {
-		prefix = "✓"
+// filterTasks removes any tasks that don't have the tag being reported on
+func (svc *service) filterTasks(tasks []int) []int {
+	if svc.tag == "" {
+		return tasks
 	}
 
+	filtered := []int{}
+
 	for _, task := range tasks {
-		fmt.Printf("%s %s\n", prefix, svc.tasks.ReportString(task))
+		if svc.tasks.HasTag(task, svc.tag) {
+			filtered = append(filtered, task)
+		}
 	}
 
-	fmt.Println()
+	return filtered
 }
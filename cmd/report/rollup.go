@@ -0,0 +1,112 @@
+package report
+
+import (
+	"log"
+	"slices"
+	"strings"
+)
+
+// rollupReport sums up every session in a period. tasks are counted once no
+// matter how many sessions they were planned in, Unfinished holds the tasks
+// that were planned in more than one session but weren't finished by the end
+// of the period.
+type rollupReport struct {
+	Title string `json:"title"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	SessionCount   int `json:"session_count"`
+	PlannedCount   int `json:"planned_count"`
+	CompletedCount int `json:"completed_count"`
+	TrackedSeconds int `json:"tracked_seconds"`
+	FocusBlocks    int `json:"focus_blocks"`
+
+	GroupedBy string `json:"grouped_by,omitempty"`
+
+	Completed  []taskReport  `json:"completed_tasks"`
+	Unfinished []taskReport  `json:"unfinished_tasks"`
+	Notes      []sessionNote `json:"notes"`
+}
+
+// sessionNote is the note of one of the sessions in a rollup
+type sessionNote struct {
+	Session string `json:"session"`
+	Date    string `json:"date"`
+	Note    string `json:"note"`
+}
+
+func (svc *service) reportPeriod(value string) {
+	period, err := parsePeriod(value, svc.tasks.Today())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sessions, err := svc.tasks.GetSessionIDsBetween(period.start, period.end)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	slices.Reverse(sessions)
+
+	svc.out.rollup(svc.rollupReport(period, sessions))
+}
+
+// rollupReport sums up the sessions, which are in chronological order
+func (svc *service) rollupReport(period period, sessions []int) rollupReport {
+	rollup := rollupReport{
+		Title:        period.title,
+		Start:        period.start,
+		End:          period.end,
+		SessionCount: len(sessions),
+		GroupedBy:    svc.groupedBy(),
+		Completed:    []taskReport{},
+		Unfinished:   []taskReport{},
+		Notes:        []sessionNote{},
+	}
+
+	planned := []int{}
+	timesPlanned := map[int]int{}
+
+	for _, id := range sessions {
+		session := svc.tasks.GetReportSession(id)
+
+		rollup.TrackedSeconds += seconds(svc.tasks.SessionTotalTime(id))
+		rollup.FocusBlocks += svc.tasks.SessionPomodoroTotal(id)
+
+		if strings.TrimSpace(session.Note) != "" {
+			rollup.Notes = append(rollup.Notes, sessionNote{Session: session.Title, Date: session.Date, Note: session.Note})
+		}
+
+		ids := slices.Concat(svc.tasks.GetCompletedTaskIDsForSession(id), svc.tasks.GetIncompleteTaskIDsForSession(id))
+
+		for _, task := range svc.filterTasks(ids) {
+			if timesPlanned[task] == 0 {
+				planned = append(planned, task)
+			}
+
+			timesPlanned[task]++
+		}
+	}
+
+	// tasks are listed by id, which is the order they were created in
+	slices.Sort(planned)
+
+	for _, id := range planned {
+		report := svc.taskReport(sessions, id)
+		report.TimesPlanned = timesPlanned[id]
+
+		switch {
+		case report.CompletedOn != "" && report.CompletedOn <= period.end:
+			rollup.Completed = append(rollup.Completed, report)
+		case report.TimesPlanned > 1:
+			rollup.Unfinished = append(rollup.Unfinished, report)
+		}
+	}
+
+	rollup.PlannedCount = len(planned)
+	rollup.CompletedCount = len(rollup.Completed)
+	rollup.Completed = svc.treeOrder(rollup.Completed)
+	rollup.Unfinished = svc.treeOrder(rollup.Unfinished)
+
+	return rollup
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/darwinfroese/scribe/internal/database"
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+func TestRollupReport(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	tasks := task.NewService(task.NewJSONStore(database.New(false)))
+	defer tasks.Close()
+
+	for _, description := range []string{"finished", "planned twice", "planned once"} {
+		tasks.AddTask(task.Details{Description: description})
+	}
+
+	morning := tasks.StartSession("morning")
+	tasks.TogglePlanTask(0)
+	tasks.TogglePlanTask(1)
+	tasks.ToggleComplete(0)
+
+	tasks.StartSession("evening")
+	tasks.ReplanSession(morning)
+	tasks.TogglePlanTask(2)
+
+	period, err := parsePeriod(periodWeek, tasks.Today())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := tasks.GetSessionIDsBetween(period.start, period.end)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Reverse(sessions)
+
+	svc := &service{tasks: tasks}
+	rollup := svc.rollupReport(period, sessions)
+
+	if rollup.SessionCount != 2 || rollup.PlannedCount != 3 || rollup.CompletedCount != 1 {
+		t.Fatalf("expected 2 sessions with 3 tasks planned and 1 completed, got %+v", rollup)
+	}
+
+	ids := func(reports []taskReport) []int {
+		ids := []int{}
+		for _, report := range reports {
+			ids = append(ids, report.ID)
+		}
+
+		return ids
+	}
+
+	if completed := ids(rollup.Completed); !slices.Equal(completed, []int{0}) {
+		t.Errorf("expected only the finished task to be completed, got %v", completed)
+	}
+
+	// tasks planned in a single session aren't called out as unfinished
+	if unfinished := ids(rollup.Unfinished); !slices.Equal(unfinished, []int{1}) {
+		t.Fatalf("expected only the task planned twice to be unfinished, got %v", unfinished)
+	}
+
+	if rollup.Unfinished[0].TimesPlanned != 2 {
+		t.Errorf("expected the unfinished task to be planned twice, got %d", rollup.Unfinished[0].TimesPlanned)
+	}
+}
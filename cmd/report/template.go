@@ -0,0 +1,252 @@
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/darwinfroese/scribe/cmd"
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+//go:embed templates/text.tmpl
+var templates embed.FS
+
+// defaultTemplate is the plain text layout of the "text" format, it also
+// serves as an example for user templates
+var defaultTemplate = template.Must(template.New("text.tmpl").Funcs(templateFuncs).ParseFS(templates, "templates/text.tmpl"))
+
+// templateFuncs are the helpers available to report templates, they're
+// documented under "Report Templates" in the README
+var templateFuncs = template.FuncMap{
+	"header":   header,
+	"duration": trackedString,
+	"tags":     task.TagsString,
+	"join":     strings.Join,
+	"repeat":   strings.Repeat,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"date":     formatDate,
+	"weekday": func(value string) (string, error) {
+		return formatDate("Monday", value)
+	},
+	"groupTasks":    groupTasks,
+	"groupSessions": groupSessions,
+}
+
+// templateWriter renders the whole report with a text/template
+type templateWriter struct {
+	out      io.Writer
+	template *template.Template
+
+	report reportData
+}
+
+func (w *templateWriter) header(title string) {
+	w.report.Title = title
+}
+
+func (w *templateWriter) session(session sessionReport) {
+	w.report.Sessions = append(w.report.Sessions, session)
+}
+
+func (w *templateWriter) list(titles []string) {
+	w.report.Titles = append(w.report.Titles, titles...)
+}
+
+func (w *templateWriter) rollup(rollup rollupReport) {
+	w.report.Rollup = &rollup
+}
+
+// the report is rendered before it's written so a template that fails part
+// way through doesn't leave half a report behind
+func (w *templateWriter) close() error {
+	var rendered bytes.Buffer
+
+	if err := w.template.Execute(&rendered, w.report); err != nil {
+		return err
+	}
+
+	_, err := w.out.Write(rendered.Bytes())
+
+	return err
+}
+
+// loadTemplate parses the template named in the config's report templates,
+// or the template file at path if there isn't one with that name
+func loadTemplate(name string, named map[string]string) (*template.Template, error) {
+	path := name
+	if named, ok := named[name]; ok {
+		path = named
+	}
+
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+
+		path = filepath.Join(home, rest)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the report template: %w", err)
+	}
+
+	parsed, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(contents))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the report template: %w", err)
+	}
+
+	return parsed, nil
+}
+
+// header underlines title with dashes, the way headers are printed by the
+// other commands
+func header(title string) string {
+	var header strings.Builder
+
+	cmd.FprintHeader(&header, title)
+
+	return header.String()
+}
+
+// formatDate formats a date (YYYY-MM-DD) or timestamp (RFC 3339) from the
+// report with a Go time layout, empty values stay empty
+func formatDate(layout, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		parsed, err = time.Parse(time.DateOnly, value)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%q isn't a date or timestamp", value)
+	}
+
+	return parsed.Format(layout), nil
+}
+
+// taskGroup is a set of tasks that share the same Key
+type taskGroup struct {
+	Key   string
+	Tasks []taskReport
+}
+
+// groupTasks groups tasks by "priority", "parent", "tag", "due_date" or
+// "completed_on", keeping the order of the groups' first tasks. a task with
+// several tags is in the group of each tag, tasks without a value are
+// grouped under an empty key. tasks nested under another task are kept in
+// the groups of the task at the top of their tree. grouping by "" puts
+// every task in a single group with an empty key.
+func groupTasks(by string, tasks []taskReport) ([]taskGroup, error) {
+	groups := []taskGroup{}
+	rootGroups := []int{}
+
+	add := func(key string, task taskReport) {
+		idx := slices.IndexFunc(groups, func(group taskGroup) bool { return group.Key == key })
+		if idx < 0 {
+			groups = append(groups, taskGroup{Key: key})
+			idx = len(groups) - 1
+		}
+
+		groups[idx].Tasks = append(groups[idx].Tasks, task)
+		rootGroups = append(rootGroups, idx)
+	}
+
+	for _, task := range tasks {
+		if task.Depth > 0 && len(rootGroups) > 0 {
+			for _, idx := range rootGroups {
+				groups[idx].Tasks = append(groups[idx].Tasks, task)
+			}
+
+			continue
+		}
+
+		rootGroups = []int{}
+
+		switch by {
+		case "":
+			add("", task)
+		case groupByPriority:
+			add(task.Priority, task)
+		case "parent":
+			add(strings.Join(task.Path, " > "), task)
+		case "due_date":
+			add(task.DueDate, task)
+		case "completed_on":
+			add(task.CompletedOn, task)
+		case "tag":
+			if len(task.Tags) == 0 {
+				add("", task)
+			}
+
+			for _, tag := range task.Tags {
+				add(tag, task)
+			}
+		default:
+			return nil, fmt.Errorf("unable to group tasks by %q", by)
+		}
+	}
+
+	return groups, nil
+}
+
+// sessionGroup is a set of sessions that share the same Key
+type sessionGroup struct {
+	Key      string
+	Sessions []sessionReport
+}
+
+// groupSessions groups sessions by "date", "name", "week" (the ISO week,
+// e.g. "2025-W02") or "month" (e.g. "2025-01"), keeping the order of the
+// groups' first sessions
+func groupSessions(by string, sessions []sessionReport) ([]sessionGroup, error) {
+	groups := []sessionGroup{}
+
+	for _, session := range sessions {
+		var key string
+
+		switch by {
+		case "date":
+			key = session.Date
+		case "name":
+			key = session.Name
+		case "week", "month":
+			date, err := time.Parse(time.DateOnly, session.Date)
+			if err != nil {
+				return nil, err
+			}
+
+			year, week := date.ISOWeek()
+			key = fmt.Sprintf("%d-W%02d", year, week)
+
+			if by == "month" {
+				key = date.Format("2006-01")
+			}
+		default:
+			return nil, fmt.Errorf("unable to group sessions by %q", by)
+		}
+
+		idx := slices.IndexFunc(groups, func(group sessionGroup) bool { return group.Key == key })
+		if idx < 0 {
+			groups = append(groups, sessionGroup{Key: key})
+			idx = len(groups) - 1
+		}
+
+		groups[idx].Sessions = append(groups[idx].Sessions, session)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/darwinfroese/scribe/internal/config"
+)
+
+const (
+	formatText     = "text"
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+	formatCSV      = "csv"
+	formatHTML     = "html"
+)
+
+// writer outputs a report in one of the supported formats. a report is a
+// header followed by either sessions or a list of session titles, or a
+// single rollup of a period. some formats can only be written out once the
+// whole report is known so close must always be called.
+type writer interface {
+	header(title string)
+	session(session sessionReport)
+	list(titles []string)
+	rollup(rollup rollupReport)
+	close() error
+}
+
+// newWriter returns the writer for the format, or for the template (a path
+// or a name in templates) if one is given. the configured default template
+// is used when neither is given.
+func newWriter(format, name string, cfg *config.Report, out io.Writer) (writer, error) {
+	if name != "" && format != "" {
+		return nil, errors.New("a report can't have both a format and a template")
+	}
+
+	if name == "" && format == "" {
+		name = cfg.Template
+	}
+
+	if name != "" {
+		template, err := loadTemplate(name, cfg.Templates)
+		if err != nil {
+			return nil, err
+		}
+
+		return &templateWriter{out: out, template: template}, nil
+	}
+
+	switch format {
+	case "", formatText:
+		return &templateWriter{out: out, template: defaultTemplate}, nil
+	case formatMarkdown:
+		return &markdownWriter{out: out}, nil
+	case formatJSON:
+		return &jsonWriter{out: out}, nil
+	case formatCSV:
+		return newCSVWriter(out), nil
+	case formatHTML:
+		return &htmlWriter{out: out}, nil
+	default:
+		return nil, fmt.Errorf("unknown report format \"%s\", expected \"text\", \"markdown\", \"json\", \"csv\" or \"html\"", format)
+	}
+}
@@ -1,17 +1,30 @@
 package scribe
 
 import (
+	"time"
+
 	"github.com/darwinfroese/scribe/cmd"
 	"github.com/darwinfroese/scribe/internal/config"
-	"github.com/darwinfroese/scribe/internal/database"
-	"github.com/darwinfroese/scribe/internal/task"
 	"github.com/darwinfroese/scribe/internal/ui"
 )
 
 func Scribe(args *cmd.Args, cfg *config.Config) {
-	db := database.New(args.Global)
-	taskService := task.NewService(db)
+	// write errors exit until the ui is running, it handles them itself
+	taskService := cmd.OpenService(*args, cfg)
+	defer taskService.Close()
+
+	move := cfg.Session.CarryOverMode == config.CarryOverMove
+
+	if cfg.Session.CarryOver == config.CarryOverAutomatic {
+		taskService.CarryOver(move)
+	}
 
 	app := ui.New(taskService, cfg.Theme)
+	app.SetPomodoroDurations(time.Duration(cfg.Pomodoro.Work)*time.Minute, time.Duration(cfg.Pomodoro.Break)*time.Minute)
+
+	if cfg.Session.CarryOver == config.CarryOverPrompt {
+		app.PromptCarryOver(move)
+	}
+
 	app.Run()
 }
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/darwinfroese/scribe/cmd"
+	"github.com/darwinfroese/scribe/internal/config"
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+type service struct {
+	tasks *task.Service
+}
+
+func Search(args cmd.Args, cfg *config.Config) {
+	svc := &service{
+		tasks: cmd.OpenService(args, cfg),
+	}
+	defer svc.tasks.Close()
+
+	svc.printTasks(args.Query)
+	svc.printNotes(args.Query)
+}
+
+func (svc *service) printTasks(query string) {
+	ids := svc.tasks.SearchTasks(query)
+
+	cmd.PrintHeader(fmt.Sprintf("Tasks Matching \"%s\"", query))
+
+	if len(ids) == 0 {
+		fmt.Printf("\tno matching tasks\n\n")
+		return
+	}
+
+	for _, id := range ids {
+		prefix := "🡢"
+
+		if svc.tasks.IsCompleted(id) {
+			prefix = "✓"
+		}
+
+		fmt.Printf("%s %s (%s)\n", prefix, svc.tasks.ReportString(id), svc.tasks.StatusString(id))
+
+		sessions := []string{}
+		for _, session := range svc.tasks.GetSessionIDsForTask(id) {
+			sessions = append(sessions, svc.tasks.SessionTitle(session))
+		}
+
+		if len(sessions) == 0 {
+			fmt.Printf("\tnever planned\n")
+		} else {
+			fmt.Printf("\tplanned in: %s\n", strings.Join(sessions, ", "))
+		}
+	}
+
+	fmt.Println()
+}
+
+func (svc *service) printNotes(query string) {
+	ids := svc.tasks.SearchSessions(query)
+
+	if len(ids) == 0 {
+		return
+	}
+
+	cmd.PrintHeader(fmt.Sprintf("Session Notes Matching \"%s\"", query))
+
+	for _, id := range ids {
+		note := strings.ReplaceAll(svc.tasks.GetNoteForSession(id), "\n", "\n\t")
+
+		fmt.Printf("%s:\n\t%s\n", svc.tasks.SessionTitle(id), note)
+	}
+
+	fmt.Println()
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/darwinfroese/scribe/cmd"
+	"github.com/darwinfroese/scribe/internal/config"
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+type service struct {
+	tasks *task.Service
+}
+
+func Session(args cmd.Args, cfg *config.Config) {
+	svc := &service{
+		tasks: cmd.OpenService(args, cfg),
+	}
+	defer svc.tasks.Close()
+
+	switch args.Action {
+	case "start":
+		svc.start(args.Name)
+	case "stop":
+		svc.stop()
+	default:
+		svc.current()
+	}
+}
+
+func (svc *service) start(name string) {
+	id := svc.tasks.StartSession(name)
+
+	fmt.Printf("started session %s\n", svc.tasks.SessionTitle(id))
+}
+
+func (svc *service) stop() {
+	id, ok := svc.tasks.GetCurrentSessionID()
+	if !ok || !svc.tasks.StopSession() {
+		fmt.Println("no session is running")
+		return
+	}
+
+	fmt.Printf("stopped session %s\n", svc.tasks.SessionTitle(id))
+}
+
+func (svc *service) current() {
+	id, ok := svc.tasks.GetCurrentSessionID()
+	if !ok {
+		fmt.Println("no session is running")
+		return
+	}
+
+	fmt.Printf("current session: %s\n", svc.tasks.SessionDisplayStringPlainText(id))
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const barWidth = 20
+
+// sparks are plain ASCII so sparklines show up in any terminal or font
+var sparks = []rune(" .:-=+*#")
+
+// bar draws a ratio (0-1) as a bar, e.g. "[#####---------------]" for 0.25
+func bar(ratio float64) string {
+	filled := int(math.Round(ratio * barWidth))
+	filled = max(0, min(filled, barWidth))
+
+	return fmt.Sprintf("[%s%s]", strings.Repeat("#", filled), strings.Repeat("-", barWidth-filled))
+}
+
+func percent(ratio float64) string {
+	return fmt.Sprintf("%3.0f%%", ratio*100)
+}
+
+// sparkline draws each value as a character scaled against the largest
+// value, anything above zero is at least a "."
+func sparkline(values []int) string {
+	highest := 0
+	for _, value := range values {
+		highest = max(highest, value)
+	}
+
+	var line strings.Builder
+
+	for _, value := range values {
+		idx := 0
+		if value > 0 {
+			idx = max(1, value*(len(sparks)-1)/highest)
+		}
+
+		line.WriteRune(sparks[idx])
+	}
+
+	return line.String()
+}
+
+// durationString describes a number of seconds in the largest two units,
+// e.g. "2d 3h" or "45m"
+func durationString(seconds int) string {
+	if seconds == 0 {
+		return "-"
+	}
+
+	duration := time.Duration(seconds) * time.Second
+	days := int(duration.Hours()) / 24
+	hours := int(duration.Hours()) % 24
+	minutes := int(duration.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return "<1m"
+	}
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"testing"
+)
+
+func TestBar(t *testing.T) {
+	tests := map[float64]string{
+		0:    "[--------------------]",
+		0.25: "[#####---------------]",
+		1:    "[####################]",
+		1.5:  "[####################]",
+	}
+
+	for ratio, expected := range tests {
+		if got := bar(ratio); got != expected {
+			t.Errorf("expected %v to be drawn as %q, got %q", ratio, expected, got)
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int
+		expected string
+	}{
+		{name: "empty", values: []int{}, expected: ""},
+		{name: "all zero", values: []int{0, 0}, expected: "  "},
+		{name: "scaled to the largest", values: []int{0, 1, 7, 14}, expected: " .-#"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := sparkline(test.values); got != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	tests := map[int]string{
+		0:                    "-",
+		30:                   "<1m",
+		45 * 60:              "45m",
+		2*3600 + 5*60:        "2h 5m",
+		2*86400 + 3*3600 + 1: "2d 3h",
+	}
+
+	for seconds, expected := range tests {
+		if got := durationString(seconds); got != expected {
+			t.Errorf("expected %d seconds to be %q, got %q", seconds, expected, got)
+		}
+	}
+}
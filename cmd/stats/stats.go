@@ -0,0 +1,258 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/darwinfroese/scribe/cmd"
+	"github.com/darwinfroese/scribe/internal/config"
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+type service struct {
+	tasks *task.Service
+}
+
+// counts are the totals for the tasks planned in one or more sessions, a
+// task planned in several sessions is counted in each of them. Completed only
+// counts the tasks completed by the end of their session, unlike reports
+// which list every planned task that has been completed since.
+type counts struct {
+	Planned     int `json:"planned"`
+	Completed   int `json:"completed_in_session"`
+	CarriedOver int `json:"carried_over"`
+
+	// CompletionRatio is the share of the planned tasks that were completed
+	// by the end of their session, CarryOverRate is the share that were
+	// carried over from a previous session
+	CompletionRatio float64 `json:"completion_ratio"`
+	CarryOverRate   float64 `json:"carry_over_rate"`
+}
+
+// stats are the statistics for the sessions in a date range, AverageToComplete
+// is the average time (in seconds) from when a task was first planned to when
+// it was completed
+type stats struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+
+	counts
+	AverageToComplete int `json:"average_seconds_to_complete"`
+
+	Sessions   []sessionStats  `json:"sessions"`
+	Priorities []priorityStats `json:"priorities"`
+}
+
+type sessionStats struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Date  string `json:"date"`
+
+	counts
+}
+
+type priorityStats struct {
+	Priority string `json:"priority"`
+	level    int
+
+	counts
+	AverageToComplete int `json:"average_seconds_to_complete"`
+}
+
+func Stats(args cmd.Args, cfg *config.Config) {
+	svc := &service{
+		tasks: cmd.OpenService(args, cfg),
+	}
+	defer svc.tasks.Close()
+
+	stats := svc.stats(args.Start, args.End)
+
+	if args.JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(stats); err != nil {
+			log.Fatal("unable to write the stats: ", err)
+		}
+
+		return
+	}
+
+	svc.print(stats)
+}
+
+// stats collects the statistics for the sessions from start to end, every
+// session is included when neither is given. the range starts at the first
+// session without a start and ends today without an end.
+func (svc *service) stats(start, end string) stats {
+	stats := stats{Start: start, End: end, Sessions: []sessionStats{}, Priorities: []priorityStats{}}
+
+	ids := svc.tasks.GetAllSessionIDs(false)
+
+	if start != "" || end != "" {
+		if stats.Start == "" && len(ids) > 0 {
+			stats.Start = svc.tasks.GetSessionDate(ids[0])
+		}
+
+		if stats.End == "" {
+			stats.End = svc.tasks.Today()
+		}
+
+		between, err := svc.tasks.GetSessionIDsBetween(stats.Start, stats.End)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if stats.End < stats.Start {
+			log.Fatalf("the end date %s is before the start date %s", stats.End, stats.Start)
+		}
+
+		ids = between
+		slices.Reverse(ids)
+	}
+
+	// a task planned in several sessions only took one stretch of time to
+	// complete, so the averages count each task once
+	toComplete := map[int]time.Duration{}
+	priorities := map[int]*priorityStats{}
+	priorityToComplete := map[int]map[int]time.Duration{}
+
+	for _, id := range ids {
+		session := sessionStats{
+			ID:    id,
+			Title: svc.tasks.SessionTitle(id),
+			Date:  svc.tasks.GetSessionDate(id),
+		}
+
+		for _, planned := range svc.tasks.GetPlannedTaskStats(id) {
+			priority, ok := priorities[planned.PriorityLevel]
+			if !ok {
+				priority = &priorityStats{Priority: planned.Priority, level: planned.PriorityLevel}
+				priorities[planned.PriorityLevel] = priority
+				priorityToComplete[planned.PriorityLevel] = map[int]time.Duration{}
+			}
+
+			session.add(planned)
+			priority.add(planned)
+			stats.add(planned)
+
+			if planned.ToComplete > 0 {
+				toComplete[planned.ID] = planned.ToComplete
+				priorityToComplete[planned.PriorityLevel][planned.ID] = planned.ToComplete
+			}
+		}
+
+		session.ratios()
+		stats.Sessions = append(stats.Sessions, session)
+	}
+
+	stats.ratios()
+	stats.AverageToComplete = averageSeconds(toComplete)
+
+	for level, priority := range priorities {
+		priority.ratios()
+		priority.AverageToComplete = averageSeconds(priorityToComplete[level])
+
+		stats.Priorities = append(stats.Priorities, *priority)
+	}
+
+	slices.SortFunc(stats.Priorities, func(a, b priorityStats) int {
+		return a.level - b.level
+	})
+
+	return stats
+}
+
+func (c *counts) add(planned task.PlannedTaskStats) {
+	c.Planned++
+
+	if planned.Completed {
+		c.Completed++
+	}
+
+	if planned.CarriedOver {
+		c.CarriedOver++
+	}
+}
+
+func (c *counts) ratios() {
+	if c.Planned == 0 {
+		return
+	}
+
+	c.CompletionRatio = float64(c.Completed) / float64(c.Planned)
+	c.CarryOverRate = float64(c.CarriedOver) / float64(c.Planned)
+}
+
+func averageSeconds(durations map[int]time.Duration) int {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+
+	return int((total / time.Duration(len(durations))).Round(time.Second).Seconds())
+}
+
+func (svc *service) print(stats stats) {
+	title := "Stats For All Sessions"
+	if stats.Start != "" {
+		title = fmt.Sprintf("Stats For Sessions Between %s And %s", stats.Start, stats.End)
+	}
+
+	cmd.PrintHeader(title)
+
+	if len(stats.Sessions) == 0 {
+		fmt.Println("\tthere are no sessions to report on")
+		return
+	}
+
+	completed := []int{}
+	for _, session := range stats.Sessions {
+		completed = append(completed, session.Completed)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "sessions:\t%d\n", len(stats.Sessions))
+	fmt.Fprintf(w, "tasks planned:\t%d\n", stats.Planned)
+	fmt.Fprintf(w, "tasks completed in session:\t%d\n", stats.Completed)
+	fmt.Fprintf(w, "plan completion:\t%s %s\n", percent(stats.CompletionRatio), bar(stats.CompletionRatio))
+	fmt.Fprintf(w, "carry-over rate:\t%s %s\n", percent(stats.CarryOverRate), bar(stats.CarryOverRate))
+	fmt.Fprintf(w, "average time to complete:\t%s\n", durationString(stats.AverageToComplete))
+	fmt.Fprintf(w, "completed per session:\t%s\n", sparkline(completed))
+	w.Flush()
+
+	fmt.Println()
+	cmd.PrintHeader("Sessions")
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "session\tplanned\tcompleted in session\tcarried over\tcompletion")
+	for _, session := range stats.Sessions {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s %s\n",
+			session.Title, session.Planned, session.Completed, session.CarriedOver, percent(session.CompletionRatio), bar(session.CompletionRatio))
+	}
+	w.Flush()
+
+	fmt.Println()
+	cmd.PrintHeader("By Priority")
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "priority\tplanned\tcompleted in session\tcarried over\tcompletion\ttime to complete")
+	for _, priority := range stats.Priorities {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s %s\t%s\n",
+			priority.Priority, priority.Planned, priority.Completed, priority.CarriedOver,
+			percent(priority.CompletionRatio), bar(priority.CompletionRatio), durationString(priority.AverageToComplete))
+	}
+	w.Flush()
+}
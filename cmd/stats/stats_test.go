@@ -0,0 +1,58 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/darwinfroese/scribe/internal/database"
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+func TestStats(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	tasks := task.NewService(task.NewJSONStore(database.New(false)))
+	defer tasks.Close()
+
+	tasks.AddTask(task.Details{Description: "critical", Priority: 0})
+	tasks.AddTask(task.Details{Description: "medium", Priority: 2})
+	tasks.AddTask(task.Details{Description: "unplanned", Priority: 2})
+
+	tasks.TogglePlanTask(0)
+	tasks.TogglePlanTask(1)
+	tasks.ToggleComplete(0)
+
+	svc := &service{tasks: tasks}
+	stats := svc.stats("", "")
+
+	if len(stats.Sessions) != 1 {
+		t.Fatalf("expected one session, got %d", len(stats.Sessions))
+	}
+
+	expected := counts{Planned: 2, Completed: 1, CompletionRatio: 0.5}
+	if stats.counts != expected {
+		t.Errorf("expected the totals to be %+v, got %+v", expected, stats.counts)
+	}
+
+	if stats.Sessions[0].counts != expected {
+		t.Errorf("expected the session totals to be %+v, got %+v", expected, stats.Sessions[0].counts)
+	}
+
+	if len(stats.Priorities) != 2 {
+		t.Fatalf("expected two priorities, got %+v", stats.Priorities)
+	}
+
+	for idx, priority := range []string{"Critical", "Medium"} {
+		if stats.Priorities[idx].Priority != priority {
+			t.Errorf("expected the priorities to be ordered by level, got %+v", stats.Priorities)
+		}
+	}
+
+	if stats.Priorities[0].CompletionRatio != 1 || stats.Priorities[1].CompletionRatio != 0 {
+		t.Errorf("expected only the critical task to be completed, got %+v", stats.Priorities)
+	}
+
+	// a range that ends before the session doesn't include it
+	if stats := svc.stats("2000-01-01", "2000-01-02"); len(stats.Sessions) != 0 || stats.Planned != 0 {
+		t.Errorf("expected no sessions before the range ended, got %+v", stats)
+	}
+}
@@ -0,0 +1,79 @@
+package trash
+
+import (
+	"fmt"
+
+	"github.com/darwinfroese/scribe/cmd"
+	"github.com/darwinfroese/scribe/internal/config"
+	"github.com/darwinfroese/scribe/internal/task"
+)
+
+type service struct {
+	tasks *task.Service
+}
+
+func Trash(args cmd.Args, cfg *config.Config) {
+	svc := &service{
+		tasks: cmd.OpenService(args, cfg),
+	}
+	defer svc.tasks.Close()
+
+	switch args.Action {
+	case "restore":
+		svc.restore(args.TaskIDs)
+	case "purge":
+		if args.All {
+			svc.purgeAll()
+			return
+		}
+
+		svc.purge(args.TaskIDs)
+	default:
+		svc.list()
+	}
+}
+
+func (svc *service) list() {
+	ids := svc.tasks.GetDeletedTaskIDs()
+
+	cmd.PrintHeader("Deleted Tasks")
+
+	if len(ids) == 0 {
+		fmt.Println("\tthe trash is empty")
+		return
+	}
+
+	for _, id := range ids {
+		fmt.Printf("\t%d\t%s\n", id, svc.tasks.TrashString(id))
+	}
+}
+
+func (svc *service) restore(ids []int) {
+	for _, id := range ids {
+		if !svc.tasks.RestoreTask(id) {
+			fmt.Printf("no deleted task with id %d\n", id)
+			continue
+		}
+
+		fmt.Printf("restored %s\n", svc.tasks.FormDisplayString(id))
+	}
+}
+
+func (svc *service) purge(ids []int) {
+	for _, id := range ids {
+		description := svc.tasks.TrashString(id)
+
+		if !svc.tasks.PurgeTask(id) {
+			fmt.Printf("no deleted task with id %d\n", id)
+			continue
+		}
+
+		fmt.Printf("permanently deleted %s\n", description)
+	}
+}
+
+func (svc *service) purgeAll() {
+	count := svc.tasks.PurgeTrash()
+
+	fmt.Printf("permanently deleted %d tasks\n", count)
+}
@@ -5,13 +5,75 @@ import (
 	"io"
 	"log"
 	"os"
+	"time"
 
+	"github.com/darwinfroese/scribe/internal/database"
 	"github.com/darwinfroese/scribe/internal/theme"
 	"github.com/pelletier/go-toml"
 )
 
+const (
+	defaultBackupCount = 10
+
+	defaultPomodoroWork  = 25
+	defaultPomodoroBreak = 5
+
+	CarryOverOff       = "off"
+	CarryOverPrompt    = "prompt"
+	CarryOverAutomatic = "automatic"
+
+	CarryOverCopy = "copy"
+	CarryOverMove = "move"
+)
+
 type Config struct {
-	Theme *theme.Theme
+	Theme    *theme.Theme
+	Database *Database
+	Backup   *Backup
+	Session  *Session
+	Pomodoro *Pomodoro
+	Report   *Report
+}
+
+type Database struct {
+	// Backend is the storage backend to use, either "json" or "sqlite"
+	Backend string
+}
+
+type Backup struct {
+	// Count is the number of snapshots to keep for each database, 0 disables backups
+	Count int
+}
+
+type Session struct {
+	// CarryOver is what happens to the unfinished tasks of the previous
+	// session on the first launch of a day, either "off", "prompt" or
+	// "automatic"
+	CarryOver string
+	// CarryOverMode is either "copy" to keep carried over tasks planned in
+	// the previous session as well or "move" to remove them from it
+	CarryOverMode string
+	// Rollover is the hour (0-23) that a new session starts at, so work done
+	// after midnight can count towards the previous day
+	Rollover int
+	// Timezone is the IANA name of the timezone that session dates are in,
+	// the local timezone is used if it isn't set
+	Timezone string
+}
+
+type Pomodoro struct {
+	// Work is the length of a focus block in minutes
+	Work int
+	// Break is the length of the break after a focus block in minutes
+	Break int
+}
+
+type Report struct {
+	// Template is the template that reports are rendered with when neither
+	// a format nor a template is given, either a path or a name in Templates
+	Template string
+	// Templates maps names to template files so they can be used by name
+	Templates map[string]string
 }
 
 func Load() *Config {
@@ -49,18 +111,85 @@ func Load() *Config {
 	}
 
 	config.parse(contents)
+
+	if config.Theme == nil {
+		config.Theme = &theme.Theme{Base: "default"}
+	}
+
+	if config.Database == nil {
+		config.Database = &Database{Backend: database.BackendJSON}
+	}
+
+	if config.Backup == nil {
+		config.Backup = &Backup{Count: defaultBackupCount}
+	}
+
+	if config.Session == nil {
+		config.Session = &Session{CarryOver: CarryOverOff, CarryOverMode: CarryOverCopy}
+	}
+
+	if config.Report == nil {
+		config.Report = &Report{}
+	}
+
+	if config.Pomodoro == nil {
+		config.Pomodoro = &Pomodoro{}
+	}
+
+	if config.Pomodoro.Work <= 0 {
+		config.Pomodoro.Work = defaultPomodoroWork
+	}
+
+	if config.Pomodoro.Break <= 0 {
+		config.Pomodoro.Break = defaultPomodoroBreak
+	}
+
+	if config.Session.Rollover < 0 || config.Session.Rollover > 23 {
+		log.Fatalf("the session rollover must be an hour between 0 and 23, not %d", config.Session.Rollover)
+	}
+
 	config.Theme = theme.Load(config.Theme)
 
 	return config
 }
 
+// Location returns the timezone that session dates are in
+func (session *Session) Location() *time.Location {
+	if session.Timezone == "" {
+		return time.Local
+	}
+
+	location, err := time.LoadLocation(session.Timezone)
+	if err != nil {
+		log.Fatal("unable to load the session timezone: ", err)
+	}
+
+	return location
+}
+
 func (config *Config) defaults() {
 	config.Theme = theme.Load(&theme.Theme{Base: "default"})
+	config.Database = &Database{Backend: database.BackendJSON}
+	config.Backup = &Backup{Count: defaultBackupCount}
+	config.Session = &Session{CarryOver: CarryOverOff, CarryOverMode: CarryOverCopy}
+	config.Pomodoro = &Pomodoro{Work: defaultPomodoroWork, Break: defaultPomodoroBreak}
+	config.Report = &Report{}
 }
 
 func (config *Config) parse(contents []byte) {
-	err := toml.Unmarshal(contents, config)
+	tree, err := toml.LoadBytes(contents)
+	if err == nil {
+		err = tree.Unmarshal(config)
+	}
+
 	if err != nil {
 		config.defaults()
+		return
+	}
+
+	// a backup table without a count keeps the default instead of turning
+	// backups off
+	if config.Backup != nil && !tree.Has("backup.count") {
+		config.Backup.Count = defaultBackupCount
 	}
 }
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestParseBackupCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expected int
+	}{
+		{name: "count", contents: "[backup]\ncount = 3\n", expected: 3},
+		{name: "backups turned off", contents: "[backup]\ncount = 0\n", expected: 0},
+		{name: "table without a count", contents: "[backup]\n", expected: defaultBackupCount},
+		{name: "invalid config", contents: "[backup\n", expected: defaultBackupCount},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &Config{}
+			config.parse([]byte(test.contents))
+
+			if config.Backup.Count != test.expected {
+				t.Errorf("parsed a count of %d, expected %d", config.Backup.Count, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	backupFolderName    = "backups"
+	backupFileExtension = ".bak"
+	backupTimeFormat    = "2006-01-02_15-04-05"
+)
+
+// Backups manages the timestamped snapshots of a single database, snapshots
+// are kept in the global scribe folder so that local databases are backed up
+// even if their project folder is removed.
+type Backups struct {
+	dir  string
+	keep int
+}
+
+type Snapshot struct {
+	Name      string
+	Path      string
+	CreatedAt time.Time
+	Size      int64
+}
+
+// NewBackups returns the backups for the database at dbPath, keeping at most
+// keep snapshots when pruning.
+func NewBackups(dbPath string, keep int) (*Backups, error) {
+	usr, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	absPath, err := filepath.Abs(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(usr.HomeDir, globalFolderName, backupFolderName, backupFolderFor(absPath))
+
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Backups{dir: dir, keep: keep}, nil
+}
+
+// NextPath returns the path to write a new snapshot to
+func (backups *Backups) NextPath() string {
+	name := time.Now().Format(backupTimeFormat)
+
+	return filepath.Join(backups.dir, name+backupFileExtension)
+}
+
+// List returns the snapshots from oldest to newest
+func (backups *Backups) List() ([]Snapshot, error) {
+	entries, err := os.ReadDir(backups.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []Snapshot{}
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), backupFileExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		createdAt, err := time.ParseInLocation(backupTimeFormat, name, time.Local)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, Snapshot{
+			Name:      name,
+			Path:      filepath.Join(backups.dir, entry.Name()),
+			CreatedAt: createdAt,
+			Size:      info.Size(),
+		})
+	}
+
+	slices.SortFunc(snapshots, func(a, b Snapshot) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return snapshots, nil
+}
+
+// Find returns the snapshot with the given name
+func (backups *Backups) Find(name string) (Snapshot, error) {
+	snapshots, err := backups.List()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	name = strings.TrimSuffix(name, backupFileExtension)
+
+	for _, snapshot := range snapshots {
+		if snapshot.Name == name {
+			return snapshot, nil
+		}
+	}
+
+	return Snapshot{}, os.ErrNotExist
+}
+
+// Prune removes the oldest snapshots until there are at most keep left
+func (backups *Backups) Prune() error {
+	snapshots, err := backups.List()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for len(snapshots) > backups.keep {
+		errs = append(errs, os.Remove(snapshots[0].Path))
+		snapshots = snapshots[1:]
+	}
+
+	return errors.Join(errs...)
+}
+
+// backupFolderFor names the folder for a database after the folder it's in,
+// with a hash of its path so that projects with the same name don't collide
+func backupFolderFor(absPath string) string {
+	sum := sha256.Sum256([]byte(absPath))
+	name := strings.TrimLeft(filepath.Base(filepath.Dir(absPath)), ".")
+
+	return name + "-" + hex.EncodeToString(sum[:])[:8]
+}
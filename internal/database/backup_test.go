@@ -0,0 +1,31 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPruneKeepsTheNewestSnapshots(t *testing.T) {
+	backups := &Backups{dir: t.TempDir(), keep: 2}
+
+	names := []string{"2025-01-06_09-00-00", "2025-01-07_09-00-00", "2025-01-08_09-00-00"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(backups.dir, name+backupFileExtension), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := backups.Prune(); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := backups.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snapshots) != 2 || snapshots[0].Name != names[1] || snapshots[1].Name != names[2] {
+		t.Errorf("expected the two newest snapshots to be kept, got %+v", snapshots)
+	}
+}
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"log"
@@ -14,25 +16,25 @@ const (
 
 	globalDatabaseFileName = "scribe"
 	localDatabaseFileName  = ".scribe"
+
+	BackendJSON   = "json"
+	BackendSQLite = "sqlite"
 )
 
+// ErrModified is returned when writing to a database that another process
+// has changed since it was last read.
+var ErrModified = errors.New("the database was modified by another process")
+
 type Database struct {
 	path string
+
+	// checksum is the checksum of the contents last read or written, nil
+	// until the database has been read
+	checksum []byte
 }
 
 func New(global bool) *Database {
-	var err error
-	path := "."
-	fileName := localDatabaseFileName
-
-	if global {
-		path, err = createScribeFolderIfNotExists()
-		if err != nil {
-			log.Fatal("an error occured creating the scribe folder: ", err)
-		}
-
-		fileName = globalDatabaseFileName
-	}
+	path, fileName := getDatabaseLocation(global, localDatabaseFileName, globalDatabaseFileName)
 
 	file, err := getDatabaseFile(path, fileName)
 	if err != nil {
@@ -44,12 +46,168 @@ func New(global bool) *Database {
 	}
 }
 
+// Existing returns the JSON database if the file exists, or nil without
+// creating it if it doesn't
+func Existing(global bool) *Database {
+	path, fileName := getDatabaseLocation(global, localDatabaseFileName, globalDatabaseFileName)
+	dbPath := filepath.Join(path, fileName)
+
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil
+	}
+
+	return &Database{path: dbPath}
+}
+
+// Write atomically replaces the database contents by writing to a temporary
+// file and renaming it over the database. it fails with ErrModified instead
+// of overwriting changes made by another process since the last Read.
 func (db *Database) Write(content []byte) error {
-	return os.WriteFile(db.path, content, 0644)
+	unlock, err := lock(db.path, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if db.checksum != nil {
+		current, err := os.ReadFile(db.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		if !bytes.Equal(checksum(current), db.checksum) {
+			return ErrModified
+		}
+	}
+
+	err = writeFileAtomic(db.path, content)
+	if err != nil {
+		return err
+	}
+
+	db.checksum = checksum(content)
+
+	return nil
 }
 
 func (db *Database) Read() ([]byte, error) {
-	return os.ReadFile(db.path)
+	unlock, err := lock(db.path, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	content, err := os.ReadFile(db.path)
+	if err != nil {
+		return nil, err
+	}
+
+	db.checksum = checksum(content)
+
+	return content, nil
+}
+
+func (db *Database) Path() string {
+	return db.path
+}
+
+// Copy writes a copy of the database to path
+func (db *Database) Copy(path string) error {
+	unlock, err := lock(db.path, false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	content, err := os.ReadFile(db.path)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, content)
+}
+
+// Restore replaces the database with the copy at path, any process that has
+// the database open will fail with ErrModified on its next write.
+func (db *Database) Restore(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lock(db.path, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = writeFileAtomic(db.path, content)
+	if err != nil {
+		return err
+	}
+
+	db.checksum = checksum(content)
+
+	return nil
+}
+
+func writeFileAtomic(path string, content []byte) error {
+	dir := filepath.Dir(path)
+
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := file.Name()
+
+	// if anything below fails we don't want to leave the temp file behind,
+	// once the rename succeeds this is a no-op
+	defer os.Remove(tmpPath)
+
+	_, err = file.Write(content)
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmpPath, 0644)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+func checksum(content []byte) []byte {
+	sum := sha256.Sum256(content)
+
+	return sum[:]
+}
+
+func getDatabaseLocation(global bool, localFileName, globalFileName string) (string, string) {
+	if !global {
+		return ".", localFileName
+	}
+
+	path, err := createScribeFolderIfNotExists()
+	if err != nil {
+		log.Fatal("an error occured creating the scribe folder: ", err)
+	}
+
+	return path, globalFileName
 }
 
 func createScribeFolderIfNotExists() (string, error) {
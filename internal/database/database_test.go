@@ -0,0 +1,116 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteDetectsConcurrentChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		// change is made by other after both databases have been read
+		change func(t *testing.T, other *Database)
+		fails  bool
+	}{
+		{
+			name:   "no changes",
+			change: func(t *testing.T, other *Database) {},
+		},
+		{
+			name: "written by another process",
+			change: func(t *testing.T, other *Database) {
+				mustWrite(t, other, `{"other":true}`)
+			},
+			fails: true,
+		},
+		{
+			name: "written with the same contents",
+			change: func(t *testing.T, other *Database) {
+				mustWrite(t, other, `{}`)
+			},
+		},
+		{
+			name: "restored from a backup",
+			change: func(t *testing.T, other *Database) {
+				mustWrite(t, other, `{"backup":true}`)
+
+				path := filepath.Join(t.TempDir(), "backup")
+				if err := other.Copy(path); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := other.Restore(path); err != nil {
+					t.Fatal(err)
+				}
+			},
+			fails: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+
+			db := New(false)
+			mustWrite(t, db, `{}`)
+
+			other := New(false)
+
+			mustRead(t, db)
+			mustRead(t, other)
+
+			test.change(t, other)
+
+			err := db.Write([]byte(`{"mine":true}`))
+			if test.fails && !errors.Is(err, ErrModified) {
+				t.Fatalf("expected ErrModified, got %v", err)
+			}
+
+			if !test.fails && err != nil {
+				t.Fatal(err)
+			}
+
+			if !test.fails {
+				return
+			}
+
+			// once the changes have been read it can be written again
+			mustRead(t, db)
+			mustWrite(t, db, `{"mine":true}`)
+		})
+	}
+}
+
+func TestWriteBeforeRead(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	db := New(false)
+	mustWrite(t, New(false), `{"other":true}`)
+
+	// nothing has been read so there's nothing to have been modified
+	mustWrite(t, db, `{"mine":true}`)
+
+	if content := mustRead(t, New(false)); content != `{"mine":true}` {
+		t.Errorf("read %s after writing", content)
+	}
+}
+
+func mustRead(t *testing.T, db *Database) string {
+	t.Helper()
+
+	content, err := db.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(content)
+}
+
+func mustWrite(t *testing.T, db *Database, content string) {
+	t.Helper()
+
+	if err := db.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,29 @@
+package database
+
+import (
+	"os"
+)
+
+const (
+	lockFileSuffix = ".lock"
+)
+
+// lock takes an advisory lock on a lock file next to path, blocking until
+// it's available. the returned func releases the lock.
+func lock(path string, exclusive bool) (func(), error) {
+	file, err := os.OpenFile(path+lockFileSuffix, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lockFile(file, exclusive)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return func() {
+		unlockFile(file)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,299 @@
+package database
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	globalSQLiteFileName = "scribe.db"
+	localSQLiteFileName  = ".scribe.db"
+
+	// _txlock=immediate takes the write lock when a transaction begins so
+	// that two processes can't both read the revision and then write
+	sqliteOptions = "?_txlock=immediate&_pragma=busy_timeout(5000)"
+
+	sqliteSchema = `CREATE TABLE IF NOT EXISTS records (
+		kind     TEXT    NOT NULL,
+		id       INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		data     TEXT    NOT NULL,
+		PRIMARY KEY (kind, id)
+	);
+	CREATE TABLE IF NOT EXISTS revision (
+		id    INTEGER PRIMARY KEY CHECK (id = 0),
+		value INTEGER NOT NULL
+	);
+	INSERT OR IGNORE INTO revision (id, value) VALUES (0, 0);`
+)
+
+// Record is a single JSON encoded entry stored in the sqlite database.
+// records are grouped by their kind and keep the order they were written in.
+type Record struct {
+	Kind string
+	ID   int
+	Data []byte
+}
+
+type recordKey struct {
+	kind string
+	id   int
+}
+
+type recordState struct {
+	position int
+	data     []byte
+}
+
+// SQLite stores the scribe database as individual records in an embedded
+// sqlite database so that only the records that changed need to be written.
+type SQLite struct {
+	path string
+	db   *sql.DB
+
+	// records tracks what is currently stored so writes can skip anything
+	// that hasn't changed
+	records map[recordKey]recordState
+
+	// revision is incremented on every write, if it doesn't match what we
+	// last saw another process has written to the database
+	revision int
+}
+
+func NewSQLite(global bool) *SQLite {
+	path, fileName := getDatabaseLocation(global, localSQLiteFileName, globalSQLiteFileName)
+	dbPath := filepath.Join(path, fileName)
+
+	db, err := sql.Open("sqlite", dbPath+sqliteOptions)
+	if err != nil {
+		log.Fatal("an error occured opening the scribe database file: ", err)
+	}
+
+	// sqlite only supports a single writer, sharing one connection keeps
+	// us from running into busy errors within the same process
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(sqliteSchema)
+	if err != nil {
+		log.Fatal("an error occured creating the scribe database: ", err)
+	}
+
+	return &SQLite{
+		path:    dbPath,
+		db:      db,
+		records: map[recordKey]recordState{},
+	}
+}
+
+func (db *SQLite) Read() ([]Record, error) {
+	tx, err := db.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // this is a no-op once the transaction is committed
+
+	records, states, revision, err := readRecords(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	db.records = states
+	db.revision = revision
+
+	return records, nil
+}
+
+// readRecords reads every record along with the revision they're at
+func readRecords(tx *sql.Tx) ([]Record, map[recordKey]recordState, int, error) {
+	var revision int
+
+	err := tx.QueryRow(`SELECT value FROM revision WHERE id = 0`).Scan(&revision)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	rows, err := tx.Query(`SELECT kind, id, position, data FROM records ORDER BY kind, position`)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer rows.Close()
+
+	records := []Record{}
+	states := map[recordKey]recordState{}
+
+	for rows.Next() {
+		var record Record
+		var position int
+		var data string
+
+		err = rows.Scan(&record.Kind, &record.ID, &position, &data)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+
+		record.Data = []byte(data)
+		records = append(records, record)
+		states[recordKey{record.Kind, record.ID}] = recordState{position, record.Data}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, 0, err
+	}
+
+	return records, states, revision, nil
+}
+
+// Write replaces the contents of the database with records, only the records
+// that were added, changed or moved are written and any records that are no
+// longer present are removed. it fails with ErrModified instead of
+// overwriting changes made by another process since the last Read.
+func (db *SQLite) Write(records []Record) error {
+	tx, err := db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // this is a no-op once the transaction is committed
+
+	var revision int
+
+	err = tx.QueryRow(`SELECT value FROM revision WHERE id = 0`).Scan(&revision)
+	if err != nil {
+		return err
+	}
+
+	if revision != db.revision {
+		return ErrModified
+	}
+
+	positions := map[string]int{}
+	states := map[recordKey]recordState{}
+
+	for _, record := range records {
+		key := recordKey{record.Kind, record.ID}
+		state := recordState{positions[record.Kind], record.Data}
+
+		positions[record.Kind]++
+		states[key] = state
+
+		existing, ok := db.records[key]
+		if ok && existing.position == state.position && bytes.Equal(existing.data, state.data) {
+			continue
+		}
+
+		_, err = tx.Exec(`INSERT INTO records (kind, id, position, data) VALUES (?, ?, ?, ?)
+			ON CONFLICT (kind, id) DO UPDATE SET position = excluded.position, data = excluded.data`,
+			record.Kind, record.ID, state.position, string(record.Data))
+		if err != nil {
+			return err
+		}
+	}
+
+	for key := range db.records {
+		if _, ok := states[key]; ok {
+			continue
+		}
+
+		_, err = tx.Exec(`DELETE FROM records WHERE kind = ? AND id = ?`, key.kind, key.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE revision SET value = ? WHERE id = 0`, revision+1)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	db.records = states
+	db.revision = revision + 1
+
+	return nil
+}
+
+func (db *SQLite) Path() string {
+	return db.path
+}
+
+// Copy writes a consistent copy of the database to path
+func (db *SQLite) Copy(path string) error {
+	// VACUUM INTO refuses to overwrite an existing file
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	_, err = db.db.Exec(`VACUUM INTO ?`, path)
+
+	return err
+}
+
+// Restore replaces the contents of the database with the copy at path, any
+// process that has the database open will fail with ErrModified on its next
+// write.
+func (db *SQLite) Restore(path string) error {
+	_, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.db.Exec(`ATTACH DATABASE ? AS snapshot`, path)
+	if err != nil {
+		return err
+	}
+	defer db.db.Exec(`DETACH DATABASE snapshot`)
+
+	tx, err := db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // this is a no-op once the transaction is committed
+
+	statements := []string{
+		`DELETE FROM records`,
+		`INSERT INTO records (kind, id, position, data) SELECT kind, id, position, data FROM snapshot.records`,
+		`UPDATE revision SET value = value + 1 WHERE id = 0`,
+	}
+
+	for _, statement := range statements {
+		_, err = tx.Exec(statement)
+		if err != nil {
+			return err
+		}
+	}
+
+	// this process can keep writing on top of what was restored
+	_, states, revision, err := readRecords(tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	db.records = states
+	db.revision = revision
+
+	return nil
+}
+
+func (db *SQLite) Close() error {
+	return db.db.Close()
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSQLiteWriteDetectsConcurrentChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		// change is made by other after both databases have been read
+		change func(t *testing.T, other *SQLite)
+		fails  bool
+	}{
+		{
+			name:   "no changes",
+			change: func(t *testing.T, other *SQLite) {},
+		},
+		{
+			name: "written by another process",
+			change: func(t *testing.T, other *SQLite) {
+				mustWriteRecords(t, other, Record{Kind: "task", ID: 1, Data: []byte(`{"other":true}`)})
+			},
+			fails: true,
+		},
+		{
+			name: "written with the same records",
+			change: func(t *testing.T, other *SQLite) {
+				mustWriteRecords(t, other, Record{Kind: "task", ID: 0, Data: []byte(`{}`)})
+			},
+			fails: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+
+			db := openSQLite(t)
+			mustWriteRecords(t, db, Record{Kind: "task", ID: 0, Data: []byte(`{}`)})
+
+			other := openSQLite(t)
+
+			mustReadRecords(t, db)
+			mustReadRecords(t, other)
+
+			test.change(t, other)
+
+			err := db.Write([]Record{{Kind: "task", ID: 0, Data: []byte(`{"mine":true}`)}})
+			if test.fails && !errors.Is(err, ErrModified) {
+				t.Fatalf("expected ErrModified, got %v", err)
+			}
+
+			if !test.fails && err != nil {
+				t.Fatal(err)
+			}
+
+			if !test.fails {
+				return
+			}
+
+			// once the changes have been read it can be written again
+			mustReadRecords(t, db)
+			mustWriteRecords(t, db, Record{Kind: "task", ID: 0, Data: []byte(`{"mine":true}`)})
+		})
+	}
+}
+
+func TestSQLiteWriteReplacesRecords(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	db := openSQLite(t)
+
+	mustWriteRecords(t, db,
+		Record{Kind: "task", ID: 2, Data: []byte(`{"id":2}`)},
+		Record{Kind: "task", ID: 0, Data: []byte(`{"id":0}`)},
+		Record{Kind: "session", ID: 0, Data: []byte(`{"id":0}`)},
+	)
+
+	mustWriteRecords(t, db,
+		Record{Kind: "task", ID: 0, Data: []byte(`{"id":0,"changed":true}`)},
+		Record{Kind: "task", ID: 2, Data: []byte(`{"id":2}`)},
+		Record{Kind: "task", ID: 1, Data: []byte(`{"id":1}`)},
+	)
+
+	expected := []string{
+		`task 0 {"id":0,"changed":true}`,
+		`task 2 {"id":2}`,
+		`task 1 {"id":1}`,
+	}
+
+	if records := mustReadRecords(t, openSQLite(t)); !slices.Equal(records, expected) {
+		t.Errorf("read %q, expected %q", records, expected)
+	}
+}
+
+func openSQLite(t *testing.T) *SQLite {
+	t.Helper()
+
+	db := NewSQLite(false)
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return db
+}
+
+func mustReadRecords(t *testing.T, db *SQLite) []string {
+	t.Helper()
+
+	records, err := db.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := []string{}
+	for _, record := range records {
+		read = append(read, fmt.Sprintf("%s %d %s", record.Kind, record.ID, record.Data))
+	}
+
+	return read
+}
+
+func mustWriteRecords(t *testing.T, db *SQLite, records ...Record) {
+	t.Helper()
+
+	if err := db.Write(records); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSQLiteWriteAfterRestore(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	db := openSQLite(t)
+	mustWriteRecords(t, db, Record{Kind: "task", ID: 0, Data: []byte(`{"id":0}`)})
+
+	path := "snapshot.db"
+	if err := db.Copy(path); err != nil {
+		t.Fatal(err)
+	}
+
+	mustWriteRecords(t, db, Record{Kind: "task", ID: 1, Data: []byte(`{"id":1}`)})
+
+	if err := db.Restore(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// the restore is this process's own change so writing on top of it works
+	mustWriteRecords(t, db,
+		Record{Kind: "task", ID: 0, Data: []byte(`{"id":0}`)},
+		Record{Kind: "task", ID: 2, Data: []byte(`{"id":2}`)},
+	)
+
+	expected := []string{`task 0 {"id":0}`, `task 2 {"id":2}`}
+
+	if records := mustReadRecords(t, openSQLite(t)); !slices.Equal(records, expected) {
+		t.Errorf("read %q, expected %q", records, expected)
+	}
+}
@@ -0,0 +1,125 @@
+package task
+
+import (
+	"slices"
+)
+
+// GetCarryOverTaskIDs returns the unfinished tasks that were planned in the
+// most recent session before today and haven't been planned today yet.
+// nothing is returned once the carry over has been done (or skipped) today.
+func (service *Service) GetCarryOverTaskIDs() []int {
+	ids := []int{}
+
+	if service.storage.Settings.CarryOverDate == service.today() {
+		return ids
+	}
+
+	previous := service.previousSession()
+	if previous == nil {
+		return ids
+	}
+
+	// the current session may have already been started before the carry over
+	planned := []int{}
+	if current := service.currentSession(); current != nil {
+		planned = current.PlannedTasks
+	}
+
+	for _, task := range service.getTasks(previous.PlannedTasks) {
+		if !task.Completed && !slices.Contains(planned, task.ID) {
+			ids = append(ids, task.ID)
+		}
+	}
+
+	return ids
+}
+
+// GetPreviousSessionDate returns the date of the most recent session before
+// today, or an empty string if there isn't one
+func (service *Service) GetPreviousSessionDate() string {
+	previous := service.previousSession()
+	if previous == nil {
+		return ""
+	}
+
+	return previous.Date
+}
+
+// CarryOver plans the unfinished tasks from the previous session (see
+// GetCarryOverTaskIDs) into the current session and returns how many were carried
+// over. when move is true the tasks are removed from the previous session,
+// otherwise they stay planned in both.
+func (service *Service) CarryOver(move bool) int {
+	ids := service.GetCarryOverTaskIDs()
+	if len(ids) == 0 {
+		return 0
+	}
+
+	service.checkpoint()
+
+	previous := service.previousSession()
+	current := service.getOrCreateCurrentSession()
+
+	for _, task := range service.getTasks(ids) {
+		service.setPlanned(task, true)
+		current.CarriedOver = append(current.CarriedOver, task.ID)
+
+		if move {
+			previous.PlannedTasks = slices.DeleteFunc(previous.PlannedTasks, func(id int) bool {
+				return id == task.ID
+			})
+		}
+	}
+
+	service.saveSession(previous)
+	service.saveSession(current)
+
+	service.storage.Settings.CarryOverDate = service.today()
+	service.write()
+
+	return len(ids)
+}
+
+// SkipCarryOver remembers that the carry over was declined so that it isn't
+// offered again today, it isn't undone along with other changes
+func (service *Service) SkipCarryOver() {
+	service.storage.Settings.CarryOverDate = service.today()
+	service.write()
+}
+
+// CarryOverCount returns how many times the task has been carried over, up
+// to and including the session, or 0 if it wasn't carried over into it
+func (service *Service) CarryOverCount(sessionID, taskID int) int {
+	session := service.getSession(sessionID)
+	if session == nil || !slices.Contains(session.CarriedOver, taskID) {
+		return 0
+	}
+
+	count := 0
+
+	for _, other := range service.storage.Sessions.Sessions {
+		if slices.Contains(other.CarriedOver, taskID) {
+			count++
+		}
+
+		if other == session {
+			break
+		}
+	}
+
+	return count
+}
+
+// previousSession returns the most recent session before today
+func (service *Service) previousSession() *session {
+	var previous *session
+	today := service.today()
+
+	for _, session := range service.storage.Sessions.Sessions {
+		if session.Date < today && (previous == nil || session.Date >= previous.Date) {
+			previous = session
+		}
+	}
+
+	return previous
+}
@@ -0,0 +1,102 @@
+package task
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// addPastSession adds a session on a day before today with tasks planned in it
+func addPastSession(t *testing.T, service *Service, daysAgo int, planned ...int) *session {
+	t.Helper()
+
+	date := time.Now().AddDate(0, 0, -daysAgo)
+
+	session := &session{
+		ID:           service.storage.Sessions.NextID,
+		Date:         service.dateOf(date),
+		PlannedTasks: planned,
+		StartedAt:    date,
+	}
+
+	service.storage.Sessions.NextID++
+	service.storage.Sessions.Sessions = append(service.storage.Sessions.Sessions, session)
+
+	return session
+}
+
+func TestCarryOver(t *testing.T) {
+	tests := []struct {
+		name string
+		move bool
+		// kept is what is left planned in the previous session
+		kept []int
+	}{
+		{name: "copy", move: false, kept: []int{0, 1, 2}},
+		{name: "move", move: true, kept: []int{1, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+
+			for _, description := range []string{"unfinished", "completed", "planned today"} {
+				service.AddTask(Details{Description: description})
+			}
+
+			service.ToggleComplete(1)
+			addPastSession(t, service, 3)
+			previous := addPastSession(t, service, 1, 0, 1, 2)
+
+			// tasks already planned in today's session aren't offered again
+			service.TogglePlanTask(2)
+
+			if date := service.GetPreviousSessionDate(); date != previous.Date {
+				t.Fatalf("expected the previous session to be %s, got %s", previous.Date, date)
+			}
+
+			if ids := service.GetCarryOverTaskIDs(); !slices.Equal(ids, []int{0}) {
+				t.Fatalf("expected only the unfinished task to be offered, got %v", ids)
+			}
+
+			if count := service.CarryOver(test.move); count != 1 {
+				t.Fatalf("expected 1 task to be carried over, %d were", count)
+			}
+
+			current, _ := service.GetCurrentSessionID()
+
+			if ids := service.GetTasksIDsForSession(current); !slices.Contains(ids, 0) {
+				t.Errorf("expected the task to be planned today, today's session has %v", ids)
+			}
+
+			if count := service.CarryOverCount(current, 0); count != 1 {
+				t.Errorf("expected the task to have been carried over once, got %d", count)
+			}
+
+			if !slices.Equal(previous.PlannedTasks, test.kept) {
+				t.Errorf("expected %v to be left in the previous session, found %v", test.kept, previous.PlannedTasks)
+			}
+
+			if ids := service.GetCarryOverTaskIDs(); len(ids) != 0 {
+				t.Errorf("expected nothing to be offered again today, got %v", ids)
+			}
+		})
+	}
+}
+
+func TestSkipCarryOver(t *testing.T) {
+	service, _ := newTestService(t)
+
+	service.AddTask(Details{Description: "unfinished"})
+	addPastSession(t, service, 1, 0)
+
+	service.SkipCarryOver()
+
+	if ids := service.GetCarryOverTaskIDs(); len(ids) != 0 {
+		t.Errorf("expected nothing to be offered after skipping, got %v", ids)
+	}
+
+	if count := service.CarryOver(false); count != 0 {
+		t.Errorf("expected nothing to be carried over after skipping, %d were", count)
+	}
+}
@@ -0,0 +1,91 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// dayBoundary decides which day, and so which session, a point in time
+// belongs to. a day starts at rollover o'clock in location, so work done
+// shortly after midnight can still count towards the previous day.
+type dayBoundary struct {
+	rollover int
+	location *time.Location
+}
+
+// SetDayBoundary sets the hour (0-23) that a new day, and so a new session,
+// starts at and the timezone that dates are in. by default days start at
+// midnight in the local timezone.
+func (service *Service) SetDayBoundary(rollover int, location *time.Location) {
+	service.day = dayBoundary{rollover: rollover, location: location}
+}
+
+// GetSessionIDsBetween returns the sessions from start to end (inclusive,
+// in YYYY-MM-DD format), with the most recent session first
+func (service *Service) GetSessionIDsBetween(start, end string) ([]int, error) {
+	for _, date := range []string{start, end} {
+		if _, err := service.dayStart(date); err != nil {
+			return nil, fmt.Errorf("invalid date %q, dates must be in YYYY-MM-DD format", date)
+		}
+	}
+
+	ids := []int{}
+
+	for _, id := range service.GetAllSessionIDs(true) {
+		date := service.getSession(id).Date
+
+		if date >= start && date <= end {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// now returns the current time in the configured timezone
+func (service *Service) now() time.Time {
+	return time.Now().In(service.location())
+}
+
+// Today returns the date of the current day in YYYY-MM-DD format
+func (service *Service) Today() string {
+	return service.today()
+}
+
+// today returns the date of the current day
+func (service *Service) today() string {
+	return service.dateOf(time.Now())
+}
+
+// dateOf returns the date of the day that t belongs to
+func (service *Service) dateOf(t time.Time) string {
+	return t.In(service.location()).Add(-time.Duration(service.day.rollover) * time.Hour).Format(time.DateOnly)
+}
+
+// ParseDate parses a date in YYYY-MM-DD format as midnight in the
+// configured timezone
+func (service *Service) ParseDate(value string) (time.Time, error) {
+	return time.ParseInLocation(time.DateOnly, value, service.location())
+}
+
+// dayStart returns the time that the day with the date starts at
+func (service *Service) dayStart(date string) (time.Time, error) {
+	start, err := service.ParseDate(date)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return start.Add(time.Duration(service.day.rollover) * time.Hour), nil
+}
+
+func (service *Service) isToday(session *session) bool {
+	return session.Date == service.today()
+}
+
+func (service *Service) location() *time.Location {
+	if service.day.location == nil {
+		return time.Local
+	}
+
+	return service.day.location
+}
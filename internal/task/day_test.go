@@ -0,0 +1,30 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateUsesTheSessionTimezone(t *testing.T) {
+	location, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Skip("the timezone database isn't available: ", err)
+	}
+
+	service, _ := newTestService(t)
+	service.SetDayBoundary(4, location)
+
+	date, err := service.ParseDate("2025-01-06")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := time.Date(2025, time.January, 6, 0, 0, 0, 0, location)
+	if !date.Equal(expected) || date.Location() != location {
+		t.Errorf("parsed %v, expected %v", date, expected)
+	}
+
+	if _, err := service.ParseDate("06/01/2025"); err == nil {
+		t.Error("expected a date that isn't YYYY-MM-DD to fail")
+	}
+}
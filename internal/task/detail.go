@@ -0,0 +1,107 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// tagPattern matches anything the TUI would treat as a style tag, see
+// tview.Escape
+var tagPattern = regexp.MustCompile(`(\[[a-zA-Z0-9_,;: \-\."#]+\[*)\]`)
+
+// DetailString describes everything about a task for the detail pane, text
+// entered by the user is escaped so that it's shown as it was written.
+func (service *Service) DetailString(id int) string {
+	task := service.getTask(id)
+
+	if task == nil {
+		return "unknown task"
+	}
+
+	var detail strings.Builder
+
+	fmt.Fprintf(&detail, "[::b]%s[::B]\n\n", escape(task.Description))
+
+	fmt.Fprintf(&detail, "[%s::]priority:[white::] [%s::]%s[white::]\n", SubTextColorKey, getPriorityColor(task.Priority), getPriorityString(task.Priority))
+	fmt.Fprintf(&detail, "[%s::]inherited priority:[white::] [%s::]%s[white::]\n", SubTextColorKey, getPriorityColor(task.InheritedPriority), getPriorityString(task.InheritedPriority))
+
+	if !task.DueDate.IsZero() {
+		fmt.Fprintf(&detail, "[%s::]due:[white::] %s\n", SubTextColorKey, task.DueDate.Format(time.DateOnly))
+	}
+
+	if len(task.Tags) > 0 {
+		fmt.Fprintf(&detail, "[%s::]tags:[white::] %s\n", SubTextColorKey, TagsString(task.Tags))
+	}
+
+	completed := "not completed"
+	if task.Completed {
+		completed = service.dateOf(task.CompletedAt)
+	}
+
+	fmt.Fprintf(&detail, "[%s::]completed:[white::] %s\n", SubTextColorKey, completed)
+
+	if tracked := service.TrackedTime(task.ID); tracked > 0 {
+		fmt.Fprintf(&detail, "[%s::]time tracked:[white::] %s", SubTextColorKey, DurationString(tracked))
+
+		// the subtasks' time is rolled up into their parent
+		if own := service.ownTrackedTime(task); own != tracked {
+			fmt.Fprintf(&detail, " (%s on this task)", DurationString(own))
+		}
+
+		detail.WriteString("\n")
+	}
+
+	if count := service.PomodoroCount(task.ID); count > 0 {
+		fmt.Fprintf(&detail, "[%s::]focus blocks:[white::] %d\n", SubTextColorKey, count)
+	}
+
+	if parent := service.getTask(task.Parent); task.HasParent && parent != nil {
+		fmt.Fprintf(&detail, "[%s::]parent:[white::] %s\n", SubTextColorKey, escape(service.FormDisplayString(parent.ID)))
+	}
+
+	if children := service.getTasks(task.Children); len(children) > 0 {
+		fmt.Fprintf(&detail, "\n[%s::]subtasks:[white::]\n", SubTextColorKey)
+
+		for _, child := range children {
+			prefix := "○"
+			if child.Completed {
+				prefix = "✓"
+			}
+
+			fmt.Fprintf(&detail, "  %s %s\n", prefix, escape(child.Description))
+		}
+	}
+
+	if task.Body != "" {
+		fmt.Fprintf(&detail, "\n[%s::]notes:[white::]\n%s\n", SubTextColorKey, escape(task.Body))
+	}
+
+	fmt.Fprintf(&detail, "\n[%s::]planned in:[white::]\n", SubTextColorKey)
+
+	sessions := service.GetSessionIDsForTask(task.ID)
+	if len(sessions) == 0 {
+		detail.WriteString("  never planned\n")
+	}
+
+	for _, id := range sessions {
+		fmt.Fprintf(&detail, "  %s\n", escape(service.SessionTitle(id)))
+	}
+
+	if history := service.GetHistory(task.ID); len(history) > 0 {
+		fmt.Fprintf(&detail, "\n[%s::]history:[white::]\n", SubTextColorKey)
+
+		// most recent first, since that's usually what's being looked for
+		for _, entry := range slices.Backward(history) {
+			fmt.Fprintf(&detail, "  %s\n", escape(entry))
+		}
+	}
+
+	return detail.String()
+}
+
+func escape(text string) string {
+	return tagPattern.ReplaceAllString(text, "$1[]")
+}
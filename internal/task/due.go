@@ -0,0 +1,91 @@
+package task
+
+import (
+	"slices"
+	"time"
+)
+
+// GetOverdueTaskIDsForSession returns the tasks planned in the session that
+// were overdue when it ended, a task is overdue once the day it's due has
+// passed without it being completed. sessions that haven't ended yet are
+// checked against the current time.
+func (service *Service) GetOverdueTaskIDsForSession(id int) []int {
+	ids := []int{}
+
+	session := service.getSession(id)
+	if session == nil {
+		return ids
+	}
+
+	ended := service.sessionEnd(session)
+	if now := time.Now(); ended.IsZero() || now.Before(ended) {
+		ended = now
+	}
+
+	for _, task := range service.storage.Tasks.Tasks {
+		if !slices.Contains(session.PlannedTasks, task.ID) {
+			continue
+		}
+
+		if service.isOverdueAt(task, ended) {
+			ids = append(ids, task.ID)
+		}
+	}
+
+	return ids
+}
+
+// DueDateString returns the due date of a task, or an empty string if it
+// doesn't have one
+func (service *Service) DueDateString(id int) string {
+	task := service.getTask(id)
+
+	if task == nil || task.DueDate.IsZero() {
+		return ""
+	}
+
+	return task.DueDate.Format(time.DateOnly)
+}
+
+// isOverdueAt returns true if the task was past the end of the day it was due
+// and not yet completed at t
+func (service *Service) isOverdueAt(task *task, t time.Time) bool {
+	if task.DueDate.IsZero() {
+		return false
+	}
+
+	due, err := service.dayStart(task.DueDate.Format(time.DateOnly))
+	if err != nil {
+		return false
+	}
+
+	deadline := due.AddDate(0, 0, 1)
+
+	if t.Before(deadline) {
+		return false
+	}
+
+	return !task.Completed || !task.CompletedAt.Before(t)
+}
+
+func dueDateString(dueDate time.Time) string {
+	if dueDate.IsZero() {
+		return "none"
+	}
+
+	return dueDate.Format(time.DateOnly)
+}
+
+func (service *Service) getDueDateColor(dueDate time.Time) string {
+	due := dueDate.Format(time.DateOnly)
+	today := service.today()
+
+	switch {
+	case due < today:
+		return OverdueColorKey
+	case due == today:
+		return DueTodayColorKey
+	default:
+		return SubTextColorKey
+	}
+}
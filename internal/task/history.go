@@ -0,0 +1,92 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	eventCreated     = "created"
+	eventEdited      = "edited"
+	eventNotesEdited = "edited notes"
+	eventPlanned     = "planned"
+	eventUnplanned   = "unplanned"
+	eventCompleted   = "completed"
+	eventReopened    = "reopened"
+	eventNested      = "nested"
+	eventUnnested    = "unnested"
+	eventDeleted     = "deleted"
+	eventRestored    = "restored"
+
+	historyTimeFormat = "2006-01-02 15:04"
+)
+
+// event is a single entry in a task's history, which is only ever appended
+// to. From and To hold the old and new values of an edited Field, or the
+// parent a task was nested under (To) or un-nested from (From). Session is
+// the session a task was planned in or unplanned from. notes can be long so
+// only the fact that they were edited is recorded.
+type event struct {
+	Kind    string    `json:"kind"`
+	At      time.Time `json:"at"`
+	Field   string    `json:"field,omitempty"`
+	From    string    `json:"from,omitempty"`
+	To      string    `json:"to,omitempty"`
+	Session int       `json:"session"`
+}
+
+// GetHistory returns a line describing each event in the task's history,
+// oldest first
+func (service *Service) GetHistory(id int) []string {
+	task := service.getTask(id)
+	if task == nil {
+		return []string{}
+	}
+
+	history := []string{}
+
+	for _, event := range task.History {
+		history = append(history, fmt.Sprintf("%s %s", event.At.Format(historyTimeFormat), service.eventString(event)))
+	}
+
+	return history
+}
+
+func (service *Service) eventString(event event) string {
+	switch event.Kind {
+	case eventEdited:
+		return fmt.Sprintf("edited %s: %s → %s", event.Field, event.From, event.To)
+	case eventPlanned:
+		return fmt.Sprintf("planned in session %s", service.sessionName(event.Session))
+	case eventUnplanned:
+		return fmt.Sprintf("unplanned from session %s", service.sessionName(event.Session))
+	case eventNested:
+		return fmt.Sprintf("nested under %s", event.To)
+	case eventUnnested:
+		return fmt.Sprintf("un-nested from %s", event.From)
+	default:
+		return event.Kind
+	}
+}
+
+func (service *Service) sessionName(id int) string {
+	return service.SessionTitle(id)
+}
+
+// record appends an event to the task's history
+func (service *Service) record(task *task, kind string) {
+	service.recordEvent(task, event{Kind: kind})
+}
+
+func (service *Service) recordEdit(task *task, field, from, to string) {
+	if from == to {
+		return
+	}
+
+	service.recordEvent(task, event{Kind: eventEdited, Field: field, From: from, To: to})
+}
+
+func (service *Service) recordEvent(task *task, event event) {
+	event.At = service.now()
+	task.History = append(task.History, event)
+}
@@ -0,0 +1,62 @@
+package task
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEditTaskRecordsChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     Details
+		expected []string
+	}{
+		{
+			name:     "nothing changed",
+			edit:     Details{Description: "task", Tags: []string{"docs"}, Body: "notes"},
+			expected: []string{},
+		},
+		{
+			name:     "description",
+			edit:     Details{Description: "renamed", Tags: []string{"docs"}, Body: "notes"},
+			expected: []string{"edited description: task → renamed"},
+		},
+		{
+			name:     "tags",
+			edit:     Details{Description: "task", Tags: []string{"docs", "api"}, Body: "notes"},
+			expected: []string{"edited tags: #docs → #docs #api"},
+		},
+		{
+			name:     "tags removed",
+			edit:     Details{Description: "task", Body: "notes"},
+			expected: []string{"edited tags: #docs → none"},
+		},
+		{
+			name:     "notes",
+			edit:     Details{Description: "task", Tags: []string{"docs"}, Body: "more notes"},
+			expected: []string{"edited notes"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+
+			service.AddTask(Details{Description: "task", Tags: []string{"docs"}, Body: "notes"})
+			before := len(service.GetHistory(0))
+
+			service.EditTask(0, test.edit)
+
+			edits := []string{}
+			for _, entry := range service.GetHistory(0)[before:] {
+				// drop the time the change was made at
+				edits = append(edits, strings.SplitN(entry, " ", 3)[2])
+			}
+
+			if !slices.Equal(edits, test.expected) {
+				t.Errorf("recorded %q, expected %q", edits, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package task
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// currentVersion is the schema version of the stored document, it needs to
+// be incremented with a new migration whenever the shape of the storage
+// changes in a way that older documents won't load correctly.
+const currentVersion = 10
+
+// migration upgrades a decoded document from the previous version to version.
+// documents are decoded with json.Number so ids and timestamps round trip
+// unchanged.
+type migration struct {
+	version     int
+	description string
+	migrate     func(doc map[string]any) error
+}
+
+// migrations must be kept in order of version with no gaps
+var migrations = []migration{
+	{
+		version:     1,
+		description: "add the schema version and fill in missing lists",
+		migrate:     migrateToV1,
+	},
+	{
+		version:     2,
+		description: "keep the manual task order and remember the todo sort order",
+		migrate:     migrateToV2,
+	},
+	{
+		version:     3,
+		description: "add due dates to tasks",
+		migrate:     migrateToV3,
+	},
+	{
+		version:     4,
+		description: "add tags to tasks",
+		migrate:     migrateToV4,
+	},
+	{
+		version:     5,
+		description: "add notes to tasks",
+		migrate:     migrateToV5,
+	},
+	{
+		version:     6,
+		description: "add a history to tasks",
+		migrate:     migrateToV6,
+	},
+	{
+		version:     7,
+		description: "add carried over tasks to sessions and the last carry over date",
+		migrate:     migrateToV7,
+	},
+	{
+		version:     8,
+		description: "add names, start and end times to sessions and mark the ones started by hand",
+		migrate:     migrateToV8,
+	},
+	{
+		version:     9,
+		description: "add tracked time to tasks",
+		migrate:     migrateToV9,
+	},
+	{
+		version:     10,
+		description: "add focus blocks to tasks",
+		migrate:     migrateToV10,
+	},
+}
+
+// zeroTime is how an unset time.Time is stored
+var zeroTime = time.Time{}.Format(time.RFC3339Nano)
+
+// documentVersion returns the schema version of content, documents written
+// before versioning was added don't have one and are version 0.
+func documentVersion(content []byte) (int, error) {
+	var doc struct {
+		Version int `json:"version"`
+	}
+
+	err := json.Unmarshal(content, &doc)
+
+	return doc.Version, err
+}
+
+// migrate runs every migration after version against content and returns
+// the upgraded document.
+func migrate(content []byte, version int) ([]byte, error) {
+	doc := map[string]any{}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range migrations {
+		if migration.version <= version {
+			continue
+		}
+
+		err = migration.migrate(doc)
+		if err != nil {
+			return nil, fmt.Errorf("migrating to version %d (%s): %w", migration.version, migration.description, err)
+		}
+
+		doc["version"] = migration.version
+	}
+
+	return json.Marshal(doc)
+}
+
+func migrateToV1(doc map[string]any) error {
+	tasks := documentObject(doc, "tasks")
+	sessions := documentObject(doc, "sessions")
+
+	for _, key := range []string{"tasks", "deleted_tasks"} {
+		for _, item := range documentList(tasks, key) {
+			task, ok := item.(map[string]any)
+			if !ok {
+				return fmt.Errorf("unexpected task in %s: %v", key, item)
+			}
+
+			documentList(task, "children")
+		}
+	}
+
+	for _, item := range documentList(sessions, "sessions") {
+		session, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("unexpected session: %v", item)
+		}
+
+		documentList(session, "planned_tasks")
+	}
+
+	return nil
+}
+
+func migrateToV2(doc map[string]any) error {
+	tasks := documentObject(doc, "tasks")
+
+	// tasks were shown in the order they were added, which becomes the
+	// starting manual order
+	for idx, item := range documentList(tasks, "tasks") {
+		task, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("unexpected task: %v", item)
+		}
+
+		task["sort_index"] = idx
+	}
+
+	settings := documentObject(doc, "settings")
+	settings["todo_sort_order"] = SortOrderNone
+
+	return nil
+}
+
+func migrateToV3(doc map[string]any) error {
+	tasks, err := documentTasks(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		documentDefault(task, "due_date", zeroTime)
+	}
+
+	return nil
+}
+
+func migrateToV4(doc map[string]any) error {
+	tasks, err := documentTasks(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		documentDefault(task, "tags", []any{})
+	}
+
+	return nil
+}
+
+func migrateToV5(doc map[string]any) error {
+	tasks, err := documentTasks(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		documentDefault(task, "body", "")
+	}
+
+	return nil
+}
+
+func migrateToV6(doc map[string]any) error {
+	tasks, err := documentTasks(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		documentDefault(task, "history", []any{})
+	}
+
+	return nil
+}
+
+func migrateToV7(doc map[string]any) error {
+	sessions, err := documentSessions(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		documentDefault(session, "carried_over", []any{})
+	}
+
+	documentDefault(documentObject(doc, "settings"), "carry_over_date", "")
+
+	return nil
+}
+
+func migrateToV8(doc map[string]any) error {
+	sessions, err := documentSessions(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		documentDefault(session, "name", "")
+		documentDefault(session, "started_at", zeroTime)
+		documentDefault(session, "ended_at", zeroTime)
+		documentDefault(session, "explicit", false)
+	}
+
+	return nil
+}
+
+func migrateToV9(doc map[string]any) error {
+	tasks, err := documentTasks(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		documentDefault(task, "intervals", []any{})
+	}
+
+	return nil
+}
+
+func migrateToV10(doc map[string]any) error {
+	tasks, err := documentTasks(doc)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		documentDefault(task, "pomodoros", []any{})
+	}
+
+	return nil
+}
+
+// documentTasks returns every task in the document, deleted or not
+func documentTasks(doc map[string]any) ([]map[string]any, error) {
+	tasks := documentObject(doc, "tasks")
+	objects := []map[string]any{}
+
+	for _, key := range []string{"tasks", "deleted_tasks"} {
+		for _, item := range documentList(tasks, key) {
+			task, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("unexpected task in %s: %v", key, item)
+			}
+
+			objects = append(objects, task)
+		}
+	}
+
+	return objects, nil
+}
+
+// documentSessions returns every session in the document
+func documentSessions(doc map[string]any) ([]map[string]any, error) {
+	objects := []map[string]any{}
+
+	for _, item := range documentList(documentObject(doc, "sessions"), "sessions") {
+		session, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected session: %v", item)
+		}
+
+		objects = append(objects, session)
+	}
+
+	return objects, nil
+}
+
+// documentDefault sets key to value if it's missing or null
+func documentDefault(object map[string]any, key string, value any) {
+	if object[key] == nil {
+		object[key] = value
+	}
+}
+
+// documentObject returns the object at key, creating it if it's missing
+func documentObject(parent map[string]any, key string) map[string]any {
+	object, ok := parent[key].(map[string]any)
+	if !ok {
+		object = map[string]any{}
+		parent[key] = object
+	}
+
+	return object
+}
+
+// documentList returns the list at key, creating it if it's missing
+func documentList(parent map[string]any, key string) []any {
+	list, ok := parent[key].([]any)
+	if !ok {
+		list = []any{}
+		parent[key] = list
+	}
+
+	return list
+}
@@ -0,0 +1,237 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestMigrateToV1(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		expected string
+		fails    bool
+	}{
+		{
+			name:     "empty document",
+			doc:      `{}`,
+			expected: `{"tasks":{"tasks":[],"deleted_tasks":[]},"sessions":{"sessions":[]}}`,
+		},
+		{
+			name: "missing and null lists",
+			doc: `{"tasks":{"next_id":2,"tasks":[{"id":0},{"id":1,"children":null}],"deleted_tasks":null},
+				"sessions":{"next_id":1,"sessions":[{"id":0}]}}`,
+			expected: `{"tasks":{"next_id":2,"tasks":[{"id":0,"children":[]},{"id":1,"children":[]}],"deleted_tasks":[]},
+				"sessions":{"next_id":1,"sessions":[{"id":0,"planned_tasks":[]}]}}`,
+		},
+		{
+			name: "existing lists are kept",
+			doc: `{"tasks":{"tasks":[{"id":0,"children":[1]}],"deleted_tasks":[{"id":1,"children":[]}]},
+				"sessions":{"sessions":[{"id":0,"planned_tasks":[0]}]}}`,
+			expected: `{"tasks":{"tasks":[{"id":0,"children":[1]}],"deleted_tasks":[{"id":1,"children":[]}]},
+				"sessions":{"sessions":[{"id":0,"planned_tasks":[0]}]}}`,
+		},
+		{
+			name:  "task that isn't an object",
+			doc:   `{"tasks":{"tasks":[1]}}`,
+			fails: true,
+		},
+		{
+			name:  "session that isn't an object",
+			doc:   `{"sessions":{"sessions":["session"]}}`,
+			fails: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testMigration(t, migrateToV1, test.doc, test.expected, test.fails)
+		})
+	}
+}
+
+func TestMigrateToV2(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		expected string
+		fails    bool
+	}{
+		{
+			name:     "empty document",
+			doc:      `{}`,
+			expected: fmt.Sprintf(`{"tasks":{"tasks":[]},"settings":{"todo_sort_order":%d}}`, SortOrderNone),
+		},
+		{
+			name: "tasks are ordered as they were added",
+			doc:  `{"tasks":{"tasks":[{"id":3},{"id":1},{"id":2}],"deleted_tasks":[{"id":0}]}}`,
+			expected: fmt.Sprintf(`{"tasks":{"tasks":[{"id":3,"sort_index":0},{"id":1,"sort_index":1},{"id":2,"sort_index":2}],"deleted_tasks":[{"id":0}]},
+				"settings":{"todo_sort_order":%d}}`, SortOrderNone),
+		},
+		{
+			name:     "existing settings are kept",
+			doc:      `{"settings":{"other":true}}`,
+			expected: fmt.Sprintf(`{"tasks":{"tasks":[]},"settings":{"other":true,"todo_sort_order":%d}}`, SortOrderNone),
+		},
+		{
+			name:  "task that isn't an object",
+			doc:   `{"tasks":{"tasks":[null]}}`,
+			fails: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testMigration(t, migrateToV2, test.doc, test.expected, test.fails)
+		})
+	}
+}
+
+func testMigration(t *testing.T, migration func(doc map[string]any) error, doc, expected string, fails bool) {
+	t.Helper()
+
+	decoded := map[string]any{}
+	if err := json.Unmarshal([]byte(doc), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	err := migration(decoded)
+	if fails {
+		if err == nil {
+			t.Fatal("expected the migration to fail")
+		}
+
+		return
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !equalJSON(t, migrated, []byte(expected)) {
+		t.Errorf("got %s, expected %s", migrated, expected)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		doc     string
+	}{
+		{
+			name:    "unversioned document",
+			version: 0,
+			doc:     `{"tasks":{"next_id":1,"tasks":[{"id":0,"description":"task","completed_at":"2025-01-06T09:30:00.123456789Z"}]},"sessions":{"next_id":0}}`,
+		},
+		{
+			name:    "version 1 document",
+			version: 1,
+			doc: `{"version":1,"tasks":{"next_id":1,"tasks":[{"id":0,"description":"task","children":[],"completed_at":"2025-01-06T09:30:00.123456789Z"}],"deleted_tasks":[]},
+				"sessions":{"next_id":0,"sessions":[]}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := migrate([]byte(test.doc), test.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			version, err := documentVersion(content)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if version != currentVersion {
+				t.Errorf("migrated to version %d, expected %d", version, currentVersion)
+			}
+
+			migrated := storage{}
+			if err := json.Unmarshal(content, &migrated); err != nil {
+				t.Fatal(err)
+			}
+
+			task := migrated.Tasks.Tasks[0]
+			if task.Description != "task" || task.Children == nil || task.CompletedAt.Nanosecond() != 123456789 {
+				t.Errorf("the task wasn't migrated unchanged: %+v", task)
+			}
+
+			if migrated.Tasks.DeletedTasks == nil || migrated.Sessions.Sessions == nil {
+				t.Error("the missing lists weren't filled in")
+			}
+		})
+	}
+}
+
+func TestMigrationsAddingFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		migration func(doc map[string]any) error
+		doc       string
+		expected  string
+	}{
+		{
+			name:      "version 3",
+			migration: migrateToV3,
+			doc:       `{"tasks":{"tasks":[{"id":0},{"id":1,"due_date":"2025-01-06T00:00:00Z"}],"deleted_tasks":[{"id":2,"due_date":null}]}}`,
+			expected: `{"tasks":{"tasks":[{"id":0,"due_date":"0001-01-01T00:00:00Z"},{"id":1,"due_date":"2025-01-06T00:00:00Z"}],
+				"deleted_tasks":[{"id":2,"due_date":"0001-01-01T00:00:00Z"}]}}`,
+		},
+		{
+			name:      "version 4",
+			migration: migrateToV4,
+			doc:       `{"tasks":{"tasks":[{"id":0},{"id":1,"tags":["docs"]}]}}`,
+			expected:  `{"tasks":{"tasks":[{"id":0,"tags":[]},{"id":1,"tags":["docs"]}],"deleted_tasks":[]}}`,
+		},
+		{
+			name:      "version 5",
+			migration: migrateToV5,
+			doc:       `{"tasks":{"tasks":[{"id":0},{"id":1,"body":"notes"}]}}`,
+			expected:  `{"tasks":{"tasks":[{"id":0,"body":""},{"id":1,"body":"notes"}],"deleted_tasks":[]}}`,
+		},
+		{
+			name:      "version 6",
+			migration: migrateToV6,
+			doc:       `{"tasks":{"tasks":[{"id":0}],"deleted_tasks":[{"id":1,"history":[{"kind":"created"}]}]}}`,
+			expected:  `{"tasks":{"tasks":[{"id":0,"history":[]}],"deleted_tasks":[{"id":1,"history":[{"kind":"created"}]}]}}`,
+		},
+		{
+			name:      "version 7",
+			migration: migrateToV7,
+			doc:       `{"sessions":{"sessions":[{"id":0},{"id":1,"carried_over":[2]}]},"settings":{"todo_sort_order":4}}`,
+			expected:  `{"sessions":{"sessions":[{"id":0,"carried_over":[]},{"id":1,"carried_over":[2]}]},"settings":{"todo_sort_order":4,"carry_over_date":""}}`,
+		},
+		{
+			name:      "version 8",
+			migration: migrateToV8,
+			doc:       `{"sessions":{"sessions":[{"id":0},{"id":1,"name":"morning","started_at":"2026-10-18T09:00:00Z","ended_at":"2026-10-18T12:30:00Z","explicit":true}]}}`,
+			expected:  `{"sessions":{"sessions":[{"id":0,"name":"","started_at":"0001-01-01T00:00:00Z","ended_at":"0001-01-01T00:00:00Z","explicit":false},{"id":1,"name":"morning","started_at":"2026-10-18T09:00:00Z","ended_at":"2026-10-18T12:30:00Z","explicit":true}]}}`,
+		},
+		{
+			name:      "version 9",
+			migration: migrateToV9,
+			doc:       `{"tasks":{"tasks":[{"id":0},{"id":1,"intervals":[{"start":"2026-10-18T09:00:00Z","end":"2026-10-18T10:00:00Z","session":0}]}]}}`,
+			expected:  `{"tasks":{"tasks":[{"id":0,"intervals":[]},{"id":1,"intervals":[{"start":"2026-10-18T09:00:00Z","end":"2026-10-18T10:00:00Z","session":0}]}],"deleted_tasks":[]}}`,
+		},
+		{
+			name:      "version 10",
+			migration: migrateToV10,
+			doc:       `{"tasks":{"tasks":[{"id":0},{"id":1,"pomodoros":[{"start":"2026-10-18T09:00:00Z","end":"2026-10-18T09:25:00Z","session":0}]}]}}`,
+			expected:  `{"tasks":{"tasks":[{"id":0,"pomodoros":[]},{"id":1,"pomodoros":[{"start":"2026-10-18T09:00:00Z","end":"2026-10-18T09:25:00Z","session":0}]}],"deleted_tasks":[]}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testMigration(t, test.migration, test.doc, test.expected, false)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package task
+
+import "time"
+
+// pomodoro is a completed focus block spent on a task during a session
+type pomodoro struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Session int       `json:"session"`
+}
+
+// LogPomodoro records a completed focus block of length, ending now, against
+// the task and the current session
+func (service *Service) LogPomodoro(id int, length time.Duration) {
+	task := service.getTask(id)
+	if task == nil {
+		return
+	}
+
+	// this isn't a checkpoint, focus blocks are kept when a change is undone
+	// (see keepUnrecorded)
+	session := service.getOrCreateCurrentSession()
+	end := service.now()
+
+	task.Pomodoros = append(task.Pomodoros, pomodoro{Start: end.Add(-length), End: end, Session: session.ID})
+	service.updateTask(task)
+
+	service.write()
+}
+
+// PomodoroCount returns how many focus blocks went into the task and
+// everything below it
+func (service *Service) PomodoroCount(id int) int {
+	return service.pomodoroCount(service.getTask(id), func(pomodoro) bool { return true })
+}
+
+// SessionPomodoroCount returns how many focus blocks went into the task and
+// everything below it during the session
+func (service *Service) SessionPomodoroCount(sessionID, taskID int) int {
+	return service.pomodoroCount(service.getTask(taskID), func(pomodoro pomodoro) bool {
+		return pomodoro.Session == sessionID
+	})
+}
+
+// SessionPomodoroTotal returns how many focus blocks were completed during
+// the session, including those of tasks that have since been deleted
+func (service *Service) SessionPomodoroTotal(id int) int {
+	total := 0
+
+	for _, tasks := range [][]*task{service.storage.Tasks.Tasks, service.storage.Tasks.DeletedTasks} {
+		for _, task := range tasks {
+			for _, pomodoro := range task.Pomodoros {
+				if pomodoro.Session == id {
+					total++
+				}
+			}
+		}
+	}
+
+	return total
+}
+
+func (service *Service) pomodoroCount(root *task, include func(pomodoro) bool) int {
+	count := 0
+
+	if root == nil {
+		return count
+	}
+
+	for _, counted := range append([]*task{root}, service.descendants(root)...) {
+		for _, pomodoro := range counted.Pomodoros {
+			if include(pomodoro) {
+				count++
+			}
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,129 @@
+package task
+
+import (
+	"slices"
+	"time"
+)
+
+// ReportTask is the detail of a task that's included in reports
+type ReportTask struct {
+	ID          int      `json:"id"`
+	Description string   `json:"description"`
+	Priority    string   `json:"priority"`
+	Tags        []string `json:"tags"`
+	DueDate     string   `json:"due_date,omitempty"`
+	Notes       string   `json:"notes,omitempty"`
+
+	// Priority is the task's effective priority, the highest of its own and
+	// its incomplete subtasks' priorities. PriorityLevel orders the priorities, from 0
+	// (critical) to 3 (low).
+	PriorityLevel int `json:"priority_level"`
+
+	// CompletedOn is the date of the day the task was completed on while
+	// CompletedAt is the exact time, in RFC 3339 format
+	CompletedOn string `json:"completed_on,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty"`
+
+	// Parent is nil for top level tasks, Path holds the descriptions of the
+	// task's ancestors starting at the top level
+	Parent   *int     `json:"parent"`
+	Path     []string `json:"path"`
+	Subtasks []int    `json:"subtasks"`
+
+	// SubtasksCompleted is how many of the task's subtasks are complete
+	SubtasksCompleted int `json:"subtasks_completed"`
+}
+
+// GetReportTask returns the detail of a task for a report
+func (service *Service) GetReportTask(id int) ReportTask {
+	task := service.getTask(id)
+	if task == nil {
+		return ReportTask{ID: id, Description: "unknown task", Tags: []string{}, Path: []string{}, Subtasks: []int{}}
+	}
+
+	priority := min(task.Priority, task.InheritedPriority)
+
+	report := ReportTask{
+		ID:            task.ID,
+		Description:   task.Description,
+		Priority:      getPriorityString(priority),
+		PriorityLevel: priority,
+		Tags:          append([]string{}, task.Tags...),
+		Notes:         task.Body,
+		Path:          []string{},
+		Subtasks:      append([]int{}, task.Children...),
+	}
+
+	if !task.DueDate.IsZero() {
+		report.DueDate = task.DueDate.Format(time.DateOnly)
+	}
+
+	if task.Completed {
+		report.CompletedOn = service.dateOf(task.CompletedAt)
+		report.CompletedAt = task.CompletedAt.In(service.location()).Format(time.RFC3339)
+	}
+
+	for _, child := range service.getTasks(task.Children) {
+		if child.Completed {
+			report.SubtasksCompleted++
+		}
+	}
+
+	if task.HasParent {
+		parent := task.Parent
+		report.Parent = &parent
+	}
+
+	for _, ancestor := range service.ancestors(task) {
+		report.Path = append(report.Path, ancestor.Description)
+	}
+
+	slices.Reverse(report.Path)
+
+	return report
+}
+
+// ReportSession is the detail of a session that's included in reports
+type ReportSession struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Date  string `json:"date"`
+	Name  string `json:"name,omitempty"`
+	Note  string `json:"note"`
+
+	// StartedAt and EndedAt are in RFC 3339 format, they're empty for
+	// sessions that didn't record them or haven't ended yet
+	StartedAt string `json:"started_at,omitempty"`
+	EndedAt   string `json:"ended_at,omitempty"`
+
+	PlannedCount   int `json:"planned_count"`
+	CompletedCount int `json:"completed_count"`
+}
+
+// GetReportSession returns the detail of a session for a report
+func (service *Service) GetReportSession(id int) ReportSession {
+	session := service.getSession(id)
+	if session == nil {
+		return ReportSession{ID: id, Title: "unknown session"}
+	}
+
+	report := ReportSession{
+		ID:             session.ID,
+		Title:          service.sessionTitle(session),
+		Date:           session.Date,
+		Name:           session.Name,
+		Note:           session.Note,
+		PlannedCount:   len(session.PlannedTasks),
+		CompletedCount: len(service.GetCompletedTaskIDsForSession(session.ID)),
+	}
+
+	if !session.StartedAt.IsZero() {
+		report.StartedAt = session.StartedAt.In(service.location()).Format(time.RFC3339)
+	}
+
+	if end := service.sessionEnd(session); !end.IsZero() && !session.StartedAt.IsZero() {
+		report.EndedAt = end.In(service.location()).Format(time.RFC3339)
+	}
+
+	return report
+}
@@ -0,0 +1,139 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// SearchTasks returns the tasks, completed or not, that match query (see
+// matchesQuery)
+func (service *Service) SearchTasks(query string) []int {
+	ids := []int{}
+
+	for _, task := range service.storage.Tasks.Tasks {
+		if matchesQuery(task, query) {
+			ids = append(ids, task.ID)
+		}
+	}
+
+	return ids
+}
+
+// matchesQuery returns true if the description of the task fuzzy matches
+// query (see FuzzyMatch), or one of its tags or its body contains query.
+// fuzzy matching longer text would match nearly anything.
+func matchesQuery(task *task, query string) bool {
+	if FuzzyMatch(query, task.Description) {
+		return true
+	}
+
+	for _, tag := range task.Tags {
+		if containsQuery(tag, query) {
+			return true
+		}
+	}
+
+	return containsQuery(task.Body, query)
+}
+
+// SearchSessions returns the sessions whose note contains query, ignoring
+// case, with the most recent session first
+func (service *Service) SearchSessions(query string) []int {
+	ids := []int{}
+
+	for _, id := range service.GetAllSessionIDs(true) {
+		if containsQuery(service.getSession(id).Note, query) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// containsQuery returns true if text contains query, ignoring case and any
+// whitespace around query. an empty query doesn't match anything.
+func containsQuery(text, query string) bool {
+	query = strings.TrimSpace(query)
+
+	return query != "" && strings.Contains(strings.ToLower(text), strings.ToLower(query))
+}
+
+// GetSessionIDsForTask returns every session the task was planned in, oldest
+// first
+func (service *Service) GetSessionIDsForTask(id int) []int {
+	ids := []int{}
+
+	for _, session := range service.storage.Sessions.Sessions {
+		if slices.Contains(session.PlannedTasks, id) {
+			ids = append(ids, session.ID)
+		}
+	}
+
+	return ids
+}
+
+// SearchDisplayString is the DisplayString of a task with the characters that
+// match query highlighted in the description, tasks that only match through
+// their tags or body are underlined instead
+func (service *Service) SearchDisplayString(id int, query string) string {
+	task := service.getTask(id)
+
+	if task == nil {
+		return "unknown task"
+	}
+
+	matches, ok := fuzzyMatches(query, task.Description)
+	if !ok && matchesQuery(task, query) {
+		return fmt.Sprintf("[::u]%s[::U]", service.displayString(task, task.Description))
+	}
+
+	if !ok {
+		return service.displayString(task, task.Description)
+	}
+
+	var description strings.Builder
+
+	for idx, r := range []rune(task.Description) {
+		if slices.Contains(matches, idx) {
+			description.WriteString("[::r]" + string(r) + "[::R]")
+		} else {
+			description.WriteRune(r)
+		}
+	}
+
+	return service.displayString(task, description.String())
+}
+
+// FuzzyMatch returns true if every character of query appears in text in the
+// same order, ignoring case and any whitespace in query. an empty query
+// doesn't match anything.
+func FuzzyMatch(query, text string) bool {
+	_, ok := fuzzyMatches(query, text)
+
+	return ok
+}
+
+// fuzzyMatches returns the index of the rune in text matched by each
+// character of query
+func fuzzyMatches(query, text string) ([]int, bool) {
+	needle := []rune(strings.ToLower(strings.Join(strings.Fields(query), "")))
+	if len(needle) == 0 {
+		return nil, false
+	}
+
+	matches := []int{}
+
+	for idx, r := range []rune(text) {
+		if unicode.ToLower(r) == needle[len(matches)] {
+			matches = append(matches, idx)
+
+			if len(matches) == len(needle) {
+				return matches, true
+			}
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,63 @@
+package task
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSearchTasks(t *testing.T) {
+	service, _ := newTestService(t)
+
+	service.AddTask(Details{Description: "write the readme"})
+	service.AddTask(Details{Description: "fix the api", Tags: []string{"backend"}})
+	service.AddTask(Details{Description: "release", Body: "remember to tag the Release Notes"})
+
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{query: "rdm", expected: []int{0}},
+		{query: "back", expected: []int{1}},
+		{query: "bknd", expected: []int{}},
+		{query: "release notes", expected: []int{2}},
+		{query: "rls nts", expected: []int{}},
+		{query: "  ", expected: []int{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			if ids := service.SearchTasks(test.query); !slices.Equal(ids, test.expected) {
+				t.Errorf("found %v, expected %v", ids, test.expected)
+			}
+		})
+	}
+}
+
+func TestSearchSessions(t *testing.T) {
+	service, _ := newTestService(t)
+
+	first := service.StartSession("first")
+	service.SaveNote("Talked about the release plan")
+
+	second := service.StartSession("second")
+	service.SaveNote("a long note about the api that has most letters of the alphabet in it somewhere")
+
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{query: "release", expected: []int{first}},
+		{query: "API", expected: []int{second}},
+		{query: "the", expected: []int{second, first}},
+		{query: "rls", expected: []int{}},
+		{query: "", expected: []int{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			if ids := service.SearchSessions(test.query); !slices.Equal(ids, test.expected) {
+				t.Errorf("found %v, expected %v", ids, test.expected)
+			}
+		})
+	}
+}
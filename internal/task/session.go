@@ -7,11 +7,22 @@ import (
 	"time"
 )
 
+const sessionTimeFormat = "15:04"
+
 type session struct {
-	ID           int    `json:"id"`
-	Date         string `json:"date"`
-	Note         string `json:"note"`
-	PlannedTasks []int  `json:"planned_tasks"`
+	ID           int       `json:"id"`
+	Date         string    `json:"date"`
+	Name         string    `json:"name"`
+	Note         string    `json:"note"`
+	PlannedTasks []int     `json:"planned_tasks"`
+	CarriedOver  []int     `json:"carried_over"`
+	StartedAt    time.Time `json:"started_at"`
+	EndedAt      time.Time `json:"ended_at"`
+
+	// Explicit sessions are started and stopped by hand and run until they're
+	// stopped or the day ends, other sessions are started as needed and end
+	// with the day
+	Explicit bool `json:"explicit"`
 }
 
 type sessionStorage struct {
@@ -33,42 +44,80 @@ func (service *Service) GetAllSessionIDs(reverse bool) []int {
 	return ids
 }
 
-func (service *Service) GetAllSessionDates() []string {
-	dates := []string{}
+func (service *Service) GetSessionDate(id int) string {
+	session := service.getSession(id)
 
-	for _, session := range service.storage.Sessions.Sessions {
-		dates = append(dates, session.Date)
+	return session.Date
+}
+
+// SessionTitle identifies a session by its date, name and the time it ran,
+// e.g. "2026-10-18 morning 09:00-12:30"
+func (service *Service) SessionTitle(id int) string {
+	session := service.getSession(id)
+	if session == nil {
+		return "unknown session"
 	}
 
-	return dates
+	return service.sessionTitle(session)
 }
 
-func (service *Service) GetSessionDate(id int) string {
-	session := service.getSession(id)
+// StartSession ends the current session and starts a new one, which runs
+// until it's stopped or the day ends, and returns its ID
+func (service *Service) StartSession(name string) int {
+	service.checkpoint()
+	service.stopTimers()
 
-	return session.Date
+	if current := service.currentSession(); current != nil {
+		current.EndedAt = service.now()
+	}
+
+	session := service.newSession(strings.TrimSpace(name), true)
+
+	service.write()
+
+	return session.ID
 }
 
-func (service *Service) TogglePlanTask(taskID int) {
-	task := service.getTask(taskID)
+// StopSession ends the current session, returning false if there isn't one.
+// a new session is started for the day the next time a task is planned or a
+// note is saved.
+func (service *Service) StopSession() bool {
+	current := service.currentSession()
+	if current == nil {
+		return false
+	}
 
-	task.Planned = !task.Planned
-	service.updateTask(task)
+	service.checkpoint()
+	service.stopTimers()
 
-	if task.Planned {
-		service.planTask(taskID)
-	} else {
-		service.unplanTask(taskID)
+	current.EndedAt = service.now()
+
+	service.write()
+
+	return true
+}
+
+// GetCurrentSessionID returns the session that is running, if there is one
+func (service *Service) GetCurrentSessionID() (int, bool) {
+	current := service.currentSession()
+	if current == nil {
+		return 0, false
 	}
 
+	return current.ID, true
+}
+
+func (service *Service) TogglePlanTask(taskID int) {
+	service.checkpoint()
+
+	task := service.getTask(taskID)
+
+	service.planDescendants(!task.Planned, task)
+
 	if task.HasParent {
 		service.planParent(task.Parent)
 	}
 
-	if len(task.Children) > 0 {
-		service.planAllChildren(task.Planned, task.ID, task.Children)
-	}
-
 	service.write()
 }
 
@@ -77,7 +126,7 @@ func (service *Service) SessionDisplayString(id int) string {
 
 	format := ""
 
-	if !session.isToday() {
+	if session != service.currentSession() {
 		format = "i"
 	} else {
 		format = "b"
@@ -96,14 +145,28 @@ func (service *Service) SessionDisplayStringPlainText(id int) string {
 	completedTasks := len(service.GetCompletedTaskIDsForSession(session.ID))
 
 	return fmt.Sprintf("%s (%d/%d)",
-		session.Date,
+		service.sessionTitle(session),
 		completedTasks,
 		len(session.PlannedTasks),
 	)
 }
 
 func (service *Service) SaveNote(contents string) {
-	session := service.getOrCreateTodaysSession()
+	service.saveNote(service.getOrCreateCurrentSession(), contents)
+}
+
+// SaveNoteForSession replaces the note of a past (or the current) session
+func (service *Service) SaveNoteForSession(id int, contents string) {
+	session := service.getSession(id)
+	if session == nil {
+		return
+	}
+
+	service.saveNote(session, contents)
+}
+
+func (service *Service) saveNote(session *session, contents string) {
+	service.checkpoint()
 
 	session.Note = contents
 
@@ -111,8 +174,92 @@ func (service *Service) SaveNote(contents string) {
 	service.write()
 }
 
+// ReplanSession plans every unfinished task from the session into the current
+// session and returns how many tasks were planned
+func (service *Service) ReplanSession(id int) int {
+	session := service.getSession(id)
+	if session == nil || session == service.currentSession() {
+		return 0
+	}
+
+	unfinished := []*task{}
+
+	for _, task := range service.getTasks(session.PlannedTasks) {
+		if !task.Completed && !service.plannedInCurrentSession(task.ID) {
+			unfinished = append(unfinished, task)
+		}
+	}
+
+	if len(unfinished) == 0 {
+		return 0
+	}
+
+	service.checkpoint()
+
+	for _, task := range unfinished {
+		service.planDescendants(true, task)
+
+		if task.HasParent {
+			service.planParent(task.Parent)
+		}
+	}
+
+	service.write()
+
+	return len(unfinished)
+}
+
+// SessionDetailString describes a session for the detail pane, with its note
+// and the tasks that were planned and completed in it
+func (service *Service) SessionDetailString(id int) string {
+	session := service.getSession(id)
+	if session == nil {
+		return "unknown session"
+	}
+
+	var detail strings.Builder
+
+	fmt.Fprintf(&detail, "[::b]%s[::B]\n\n", service.SessionDisplayStringPlainText(id))
+
+	tracked, pomodoros := service.SessionTotalTime(id), service.SessionPomodoroTotal(id)
+
+	if tracked > 0 {
+		fmt.Fprintf(&detail, "[%s::]time tracked:[white::] %s\n", SubTextColorKey, DurationString(tracked))
+	}
+
+	if pomodoros > 0 {
+		fmt.Fprintf(&detail, "[%s::]focus blocks:[white::] %d\n", SubTextColorKey, pomodoros)
+	}
+
+	if tracked > 0 || pomodoros > 0 {
+		detail.WriteString("\n")
+	}
+
+	fmt.Fprintf(&detail, "[%s::]note:[white::]\n", SubTextColorKey)
+	if session.Note == "" {
+		detail.WriteString("  no note\n")
+	} else {
+		fmt.Fprintf(&detail, "%s\n", escape(session.Note))
+	}
+
+	fmt.Fprintf(&detail, "\n[%s::]completed tasks:[white::]\n", SubTextColorKey)
+	for _, taskID := range service.GetCompletedTaskIDsForSession(id) {
+		fmt.Fprintf(&detail, "  ✓ %s\n", escape(service.ReportString(taskID)))
+	}
+
+	fmt.Fprintf(&detail, "\n[%s::]unfinished tasks:[white::]\n", SubTextColorKey)
+	for _, taskID := range service.GetIncompleteTaskIDsForSession(id) {
+		fmt.Fprintf(&detail, "  ○ %s\n", escape(service.ReportString(taskID)))
+	}
+
+	return detail.String()
+}
+
 func (service *Service) GetNote() string {
-	session := service.getOrCreateTodaysSession()
+	session := service.currentSession()
+	if session == nil {
+		return ""
+	}
 
 	return session.Note
 }
@@ -124,7 +271,7 @@ func (service *Service) GetNoteForSession(id int) string {
 }
 
 func (service *Service) planTask(taskID int) {
-	session := service.getOrCreateTodaysSession()
+	session := service.getOrCreateCurrentSession()
 
 	if slices.Contains(session.PlannedTasks, taskID) {
 		return
@@ -132,15 +279,27 @@ func (service *Service) planTask(taskID int) {
 
 	session.PlannedTasks = append(session.PlannedTasks, taskID)
 	service.saveSession(session)
+
+	if planned := service.getTask(taskID); planned != nil {
+		service.recordEvent(planned, event{Kind: eventPlanned, Session: session.ID})
+	}
 }
 
 func (service *Service) unplanTask(taskID int) {
-	session := service.getOrCreateTodaysSession()
+	session := service.currentSession()
+	if session == nil {
+		return
+	}
 
 	for idx, task := range session.PlannedTasks {
 		if task == taskID {
 			session.PlannedTasks = slices.Delete(session.PlannedTasks, idx, idx+1)
 			service.saveSession(session)
+
+			if unplanned := service.getTask(taskID); unplanned != nil {
+				service.recordEvent(unplanned, event{Kind: eventUnplanned, Session: session.ID})
+			}
+
 			return
 		}
 	}
@@ -165,18 +324,41 @@ func (service *Service) getSession(id int) *session {
 	return nil
 }
 
-func (service *Service) getOrCreateTodaysSession() *session {
-	today := time.Now().Format(time.DateOnly)
+func (service *Service) getOrCreateCurrentSession() *session {
+	if current := service.currentSession(); current != nil {
+		return current
+	}
+
+	return service.newSession("", false)
+}
 
-	for _, session := range service.storage.Sessions.Sessions {
-		if session.Date == today {
+// currentSession returns the session that is running without starting one,
+// sessions from earlier days have ended even if they were never stopped
+func (service *Service) currentSession() *session {
+	for _, session := range slices.Backward(service.storage.Sessions.Sessions) {
+		if session.EndedAt.IsZero() && service.isToday(session) {
 			return session
 		}
 	}
 
+	return nil
+}
+
+func (service *Service) newSession(name string, explicit bool) *session {
+	// sessions started by hand on an earlier day and never stopped are
+	// closed at the end of their day
+	for _, previous := range service.storage.Sessions.Sessions {
+		if previous.Explicit && previous.EndedAt.IsZero() && !service.isToday(previous) {
+			previous.EndedAt = service.sessionEnd(previous)
+		}
+	}
+
 	session := session{
-		ID:   service.storage.Sessions.NextID,
-		Date: today,
+		ID:        service.storage.Sessions.NextID,
+		Date:      service.today(),
+		Name:      name,
+		StartedAt: service.now(),
+		Explicit:  explicit,
 	}
 
 	service.storage.Sessions.NextID++
@@ -185,72 +367,59 @@ func (service *Service) getOrCreateTodaysSession() *session {
 	return &session
 }
 
-func (service *Service) taskPlannedToday(id int) bool {
-	session := service.getOrCreateTodaysSession()
+// sessionEnd returns when the session ended, sessions that weren't stopped by
+// hand end with their day. the zero time is returned while a session is
+// running.
+func (service *Service) sessionEnd(session *session) time.Time {
+	if !session.EndedAt.IsZero() {
+		return session.EndedAt
+	}
 
-	if len(session.PlannedTasks) == 0 {
-		return false
+	if session == service.currentSession() {
+		return time.Time{}
 	}
 
-	if slices.Contains(session.PlannedTasks, id) {
-		return true
+	start, err := service.dayStart(session.Date)
+	if err != nil {
+		return time.Time{}
 	}
 
-	return false
+	return start.AddDate(0, 0, 1)
 }
 
-func (service *Service) planParent(parentID int) {
-	parent := service.getTask(parentID)
+func (service *Service) sessionTitle(session *session) string {
+	title := session.Date
 
-	parentPlanned := true
-	for _, childID := range parent.Children {
-		child := service.getTask(childID)
-		if !child.Planned && !child.Completed {
-			parentPlanned = false
-			break
-		}
+	if session.Name != "" {
+		title = fmt.Sprintf("%s %s", title, session.Name)
 	}
 
-	parent.Planned = parentPlanned
-	if parentPlanned {
-		service.planTask(parent.ID)
-	} else {
-		service.unplanTask(parent.ID)
+	if session.StartedAt.IsZero() {
+		return title
 	}
 
-	service.updateTask(parent)
-}
-
-func (service *Service) planAllChildren(planned bool, parentID int, children []int) {
-	parent := service.getTask(parentID)
-	parent.Planned = planned
+	started := session.StartedAt.In(service.location()).Format(sessionTimeFormat)
 
-	if parent.Planned {
-		service.planTask(parent.ID)
-	} else {
-		service.unplanTask(parent.ID)
+	switch {
+	case session == service.currentSession():
+		return fmt.Sprintf("%s %s-now", title, started)
+	case session.EndedAt.IsZero():
+		return fmt.Sprintf("%s %s", title, started)
+	default:
+		return fmt.Sprintf("%s %s-%s", title, started, session.EndedAt.In(service.location()).Format(sessionTimeFormat))
 	}
+}
 
-	for _, childID := range children {
-		child := service.getTask(childID)
-		if child.Planned == parent.Planned || child.Completed {
-			continue
-		}
-
-		child.Planned = planned
+func (service *Service) plannedInCurrentSession(id int) bool {
+	session := service.currentSession()
 
-		if child.Planned {
-			service.planTask(childID)
-		} else {
-			service.unplanTask(childID)
-		}
-
-		service.updateTask(child)
+	if session == nil || len(session.PlannedTasks) == 0 {
+		return false
 	}
 
-	service.updateTask(parent)
-}
+	if slices.Contains(session.PlannedTasks, id) {
+		return true
+	}
 
-func (session *session) isToday() bool {
-	return session.Date == time.Now().Format(time.DateOnly)
+	return false
 }
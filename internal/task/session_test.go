@@ -0,0 +1,71 @@
+package task
+
+import (
+	"testing"
+)
+
+func TestExplicitSessionEndsWithItsDay(t *testing.T) {
+	service, _ := newTestService(t)
+	service.AddTask(Details{Description: "task"})
+
+	previous := addPastSession(t, service, 1)
+	previous.Explicit = true
+
+	if id, ok := service.GetCurrentSessionID(); ok {
+		t.Fatalf("expected yesterday's session to have ended, got session %d", id)
+	}
+
+	if service.StopSession() {
+		t.Fatal("expected there to be no session to stop")
+	}
+
+	start, err := service.dayStart(previous.Date)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	end := start.AddDate(0, 0, 1)
+
+	if got := service.sessionEnd(previous); !got.Equal(end) {
+		t.Fatalf("expected yesterday's session to end at %v, got %v", end, got)
+	}
+
+	// starting today's session closes yesterday's for good
+	service.TogglePlanTask(0)
+
+	id, ok := service.GetCurrentSessionID()
+	if !ok || id == previous.ID {
+		t.Fatalf("expected a new session to be running, got %d (%t)", id, ok)
+	}
+
+	if current := service.getSession(id); !service.isToday(current) || current.Explicit {
+		t.Fatalf("expected today's session to be started for the planned task, got %+v", current)
+	}
+
+	if !previous.EndedAt.Equal(end) {
+		t.Fatalf("expected yesterday's session to be closed at %v, got %v", end, previous.EndedAt)
+	}
+}
+
+func TestStartAndStopSession(t *testing.T) {
+	service, _ := newTestService(t)
+
+	first := service.StartSession(" morning ")
+	second := service.StartSession("evening")
+
+	if id, ok := service.GetCurrentSessionID(); !ok || id != second {
+		t.Fatalf("expected session %d to be running, got %d (%t)", second, id, ok)
+	}
+
+	if session := service.getSession(first); session.Name != "morning" || session.EndedAt.IsZero() {
+		t.Fatalf("expected the first session to be named and ended, got %+v", session)
+	}
+
+	if !service.StopSession() {
+		t.Fatal("expected the running session to be stopped")
+	}
+
+	if _, ok := service.GetCurrentSessionID(); ok {
+		t.Fatal("expected no session to be running")
+	}
+}
@@ -3,7 +3,6 @@ package task
 import (
 	"cmp"
 	"strings"
-	"time"
 )
 
 const (
@@ -16,10 +15,10 @@ func (service *Service) sortOrderCompletedDateFunc(sortOrder int) func(a, b int)
 		taskA := service.getTask(a)
 		taskB := service.getTask(b)
 
-		taskADate := taskA.CompletedAt.Round(24 * time.Hour)
-		taskBDate := taskB.CompletedAt.Round(24 * time.Hour)
+		taskADate := service.dateOf(taskA.CompletedAt)
+		taskBDate := service.dateOf(taskB.CompletedAt)
 
-		order := taskADate.Compare(taskBDate)
+		order := strings.Compare(taskADate, taskBDate)
 
 		if sortOrder == sortOrderDesc {
 			return order * -1
@@ -54,3 +53,45 @@ func (service *Service) sortOrderPriorityFunc(sortOrder int) func(a, b int) int
 		return order
 	}
 }
+
+func (service *Service) sortOrderManualFunc() func(a, b int) int {
+	return func(a, b int) int {
+		taskA := service.getTask(a)
+		taskB := service.getTask(b)
+
+		order := cmp.Compare(taskA.SortIndex, taskB.SortIndex)
+
+		if order == 0 {
+			order = cmp.Compare(taskA.ID, taskB.ID)
+		}
+
+		return order
+	}
+}
+
+// sortOrderDueDateFunc orders tasks by the date they're due, soonest first,
+// then by priority. tasks without a due date are sorted after those with one.
+func (service *Service) sortOrderDueDateFunc() func(a, b int) int {
+	byPriority := service.sortOrderPriorityFunc(sortOrderAsc)
+
+	return func(a, b int) int {
+		taskA := service.getTask(a)
+		taskB := service.getTask(b)
+
+		if taskA.DueDate.IsZero() != taskB.DueDate.IsZero() {
+			if taskA.DueDate.IsZero() {
+				return 1
+			}
+
+			return -1
+		}
+
+		order := taskA.DueDate.Compare(taskB.DueDate)
+
+		if order == 0 {
+			order = byPriority(a, b)
+		}
+
+		return order
+	}
+}
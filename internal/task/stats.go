@@ -0,0 +1,67 @@
+package task
+
+import (
+	"slices"
+	"time"
+)
+
+// PlannedTaskStats is how a task planned in a session went
+type PlannedTaskStats struct {
+	ID            int
+	Priority      string
+	PriorityLevel int
+
+	// Completed is true when the task was completed by the end of the
+	// session, CarriedOver when it was carried over into the session
+	Completed   bool
+	CarriedOver bool
+
+	// ToComplete is the time from when the task was first planned to when
+	// it was completed, it's zero for unfinished tasks and for tasks that
+	// were planned before their history was recorded
+	ToComplete time.Duration
+}
+
+// GetPlannedTaskStats returns how each of the tasks planned in a session went
+func (service *Service) GetPlannedTaskStats(sessionID int) []PlannedTaskStats {
+	stats := []PlannedTaskStats{}
+
+	session := service.getSession(sessionID)
+	if session == nil {
+		return stats
+	}
+
+	ended := service.sessionEnd(session)
+
+	for _, task := range service.getTasks(session.PlannedTasks) {
+		priority := min(task.Priority, task.InheritedPriority)
+
+		planned := PlannedTaskStats{
+			ID:            task.ID,
+			Priority:      getPriorityString(priority),
+			PriorityLevel: priority,
+			Completed:     task.Completed && (ended.IsZero() || task.CompletedAt.Before(ended)),
+			CarriedOver:   slices.Contains(session.CarriedOver, task.ID),
+		}
+
+		if plannedAt := firstPlanned(task); task.Completed && !plannedAt.IsZero() && task.CompletedAt.After(plannedAt) {
+			planned.ToComplete = task.CompletedAt.Sub(plannedAt)
+		}
+
+		stats = append(stats, planned)
+	}
+
+	return stats
+}
+
+// firstPlanned returns when the task was first planned, or the zero time if
+// there isn't a record of it
+func firstPlanned(task *task) time.Time {
+	for _, event := range task.History {
+		if event.Kind == eventPlanned {
+			return event.At
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,327 @@
+package task
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/darwinfroese/scribe/internal/database"
+)
+
+const (
+	metaRecordKind        = "meta"
+	taskRecordKind        = "task"
+	deletedTaskRecordKind = "deleted_task"
+	sessionRecordKind     = "session"
+)
+
+// Store is the persistence backend used by the Service. Load returns the
+// stored document as JSON (or nothing for a new database) and Save persists
+// the full storage, backends are free to only write what has changed.
+// Backup writes a copy of the database, as it's currently stored, to path
+// and Restore replaces the stored database with a copy written by Backup.
+type Store interface {
+	Load() ([]byte, error)
+	Save(storage *storage) error
+	Backup(path string) error
+	Restore(path string) error
+	Path() string
+	Close() error
+}
+
+// collection describes a list in the stored document that backends which
+// support it store as individual records, parent and key are the json keys
+// of the list in the document.
+type collection struct {
+	kind   string
+	parent string
+	key    string
+}
+
+var collections = []collection{
+	{kind: taskRecordKind, parent: "tasks", key: "tasks"},
+	{kind: deletedTaskRecordKind, parent: "tasks", key: "deleted_tasks"},
+	{kind: sessionRecordKind, parent: "sessions", key: "sessions"},
+}
+
+// OpenStore opens the store for the configured backend, falling back to the
+// JSON file for anything that isn't recognized. a new sqlite database is
+// seeded from the JSON file in the same location if there is one.
+func OpenStore(backend string, global bool) Store {
+	switch backend {
+	case database.BackendSQLite:
+		return NewSQLiteStore(database.NewSQLite(global), database.Existing(global))
+	default:
+		return NewJSONStore(database.New(global))
+	}
+}
+
+type jsonStore struct {
+	db *database.Database
+}
+
+func NewJSONStore(db *database.Database) Store {
+	return &jsonStore{db: db}
+}
+
+func (store *jsonStore) Load() ([]byte, error) {
+	return store.db.Read()
+}
+
+func (store *jsonStore) Save(storage *storage) error {
+	content, err := json.Marshal(storage)
+	if err != nil {
+		return err
+	}
+
+	return store.db.Write(content)
+}
+
+func (store *jsonStore) Backup(path string) error {
+	return store.db.Copy(path)
+}
+
+func (store *jsonStore) Restore(path string) error {
+	return store.db.Restore(path)
+}
+
+func (store *jsonStore) Path() string {
+	return store.db.Path()
+}
+
+func (store *jsonStore) Close() error {
+	return nil
+}
+
+type sqliteStore struct {
+	db *database.SQLite
+
+	// seed is the JSON database that an empty sqlite database is seeded
+	// from, nil if there isn't one
+	seed *database.Database
+}
+
+func NewSQLiteStore(db *database.SQLite, seed *database.Database) Store {
+	return &sqliteStore{db: db, seed: seed}
+}
+
+func (store *sqliteStore) Load() ([]byte, error) {
+	records, err := store.db.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return store.importSeed()
+	}
+
+	return joinDocument(records)
+}
+
+// importSeed copies the JSON database into the empty sqlite database as it
+// is, any migrations are run on it afterwards like any other document.
+func (store *sqliteStore) importSeed() ([]byte, error) {
+	if store.seed == nil {
+		return nil, nil
+	}
+
+	content, err := store.seed.Read()
+	if err != nil || len(content) == 0 {
+		return nil, err
+	}
+
+	records, err := splitDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.db.Write(records)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf(`imported the existing database at "%s" into "%s"`, store.seed.Path(), store.db.Path())
+
+	return content, nil
+}
+
+func (store *sqliteStore) Save(storage *storage) error {
+	records, err := storageRecords(storage)
+	if err != nil {
+		return err
+	}
+
+	return store.db.Write(records)
+}
+
+func (store *sqliteStore) Backup(path string) error {
+	return store.db.Copy(path)
+}
+
+func (store *sqliteStore) Restore(path string) error {
+	return store.db.Restore(path)
+}
+
+func (store *sqliteStore) Path() string {
+	return store.db.Path()
+}
+
+func (store *sqliteStore) Close() error {
+	return store.db.Close()
+}
+
+// splitDocument breaks the document into a record for each entry of the
+// collections and a single meta record holding everything else.
+func splitDocument(content []byte) ([]database.Record, error) {
+	doc := map[string]json.RawMessage{}
+
+	err := json.Unmarshal(content, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []database.Record{}
+
+	for _, collection := range collections {
+		parent := map[string]json.RawMessage{}
+		items := []json.RawMessage{}
+
+		err = unmarshalIfPresent(doc[collection.parent], &parent)
+		if err != nil {
+			return nil, err
+		}
+
+		err = unmarshalIfPresent(parent[collection.key], &items)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			var key struct {
+				ID int `json:"id"`
+			}
+
+			err = json.Unmarshal(item, &key)
+			if err != nil {
+				return nil, err
+			}
+
+			records = append(records, database.Record{Kind: collection.kind, ID: key.ID, Data: item})
+		}
+
+		delete(parent, collection.key)
+
+		doc[collection.parent], err = json.Marshal(parent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	meta, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	records = append(records, database.Record{Kind: metaRecordKind, Data: meta})
+
+	return records, nil
+}
+
+// storageRecords breaks the storage into the same records as splitDocument
+// does for its document, encoding each task and session on its own rather
+// than encoding and then re-parsing the whole document.
+func storageRecords(storage *storage) ([]database.Record, error) {
+	records := []database.Record{}
+
+	add := func(kind string, id int, v any) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		records = append(records, database.Record{Kind: kind, ID: id, Data: data})
+		return nil
+	}
+
+	for _, task := range storage.Tasks.Tasks {
+		if err := add(taskRecordKind, task.ID, task); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, task := range storage.Tasks.DeletedTasks {
+		if err := add(deletedTaskRecordKind, task.ID, task); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, session := range storage.Sessions.Sessions {
+		if err := add(sessionRecordKind, session.ID, session); err != nil {
+			return nil, err
+		}
+	}
+
+	// the collections are filled back in by joinDocument
+	meta := *storage
+	meta.Tasks = &taskStorage{NextID: storage.Tasks.NextID}
+	meta.Sessions = &sessionStorage{NextID: storage.Sessions.NextID}
+
+	if err := add(metaRecordKind, 0, meta); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// joinDocument is the reverse of splitDocument, records are expected to be
+// in the order they were originally split in.
+func joinDocument(records []database.Record) ([]byte, error) {
+	doc := map[string]json.RawMessage{}
+	items := map[string][]json.RawMessage{}
+
+	for _, record := range records {
+		if record.Kind == metaRecordKind {
+			err := json.Unmarshal(record.Data, &doc)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		items[record.Kind] = append(items[record.Kind], record.Data)
+	}
+
+	for _, collection := range collections {
+		parent := map[string]json.RawMessage{}
+
+		err := unmarshalIfPresent(doc[collection.parent], &parent)
+		if err != nil {
+			return nil, err
+		}
+
+		list := items[collection.kind]
+		if list == nil {
+			list = []json.RawMessage{}
+		}
+
+		parent[collection.key], err = json.Marshal(list)
+		if err != nil {
+			return nil, err
+		}
+
+		doc[collection.parent], err = json.Marshal(parent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+func unmarshalIfPresent(content json.RawMessage, v any) error {
+	if len(content) == 0 || string(content) == "null" {
+		return nil
+	}
+
+	return json.Unmarshal(content, v)
+}
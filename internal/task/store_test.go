@@ -0,0 +1,128 @@
+package task
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestStorageRecordsJoinToTheStorage(t *testing.T) {
+	storage := &storage{
+		Version:  currentVersion,
+		Settings: settings{TodoSortOrder: SortOrderPriorityDesc, CarryOverDate: "2025-01-06"},
+		Tasks: &taskStorage{
+			NextID: 3,
+			Tasks: []*task{
+				{ID: 0, Description: "first", Children: []int{1}, Tags: []string{"docs"}},
+				{ID: 1, Description: "second", HasParent: true, Parent: 0, CompletedAt: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)},
+			},
+			DeletedTasks: []*task{{ID: 2, Description: "deleted"}},
+		},
+		Sessions: &sessionStorage{
+			NextID:   1,
+			Sessions: []*session{{ID: 0, Note: "a note", PlannedTasks: []int{0, 1}}},
+		},
+	}
+
+	records, err := storageRecords(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	joined, err := joinDocument(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	split, err := splitDocument(joined)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(split) != len(records) {
+		t.Fatalf("expected %d records after joining and splitting, got %d", len(records), len(split))
+	}
+
+	for idx := range records {
+		if records[idx].Kind != split[idx].Kind || records[idx].ID != split[idx].ID {
+			t.Errorf("record %d is %s %d, expected %s %d", idx, split[idx].Kind, split[idx].ID, records[idx].Kind, records[idx].ID)
+		}
+	}
+
+	expected, err := json.Marshal(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !equalJSON(t, joined, expected) {
+		t.Errorf("joined records don't match the storage\n got: %s\nwant: %s", joined, expected)
+	}
+}
+
+func equalJSON(t *testing.T, a, b []byte) bool {
+	t.Helper()
+
+	var left, right any
+
+	if err := json.Unmarshal(a, &left); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(b, &right); err != nil {
+		t.Fatal(err)
+	}
+
+	leftJSON, _ := json.Marshal(left)
+	rightJSON, _ := json.Marshal(right)
+
+	return string(leftJSON) == string(rightJSON)
+}
+
+// memoryStore keeps the stored document in memory
+type memoryStore struct {
+	content []byte
+}
+
+func newTestService(t *testing.T) (*Service, *memoryStore) {
+	t.Helper()
+
+	store := &memoryStore{}
+
+	service := NewService(store)
+	service.SetWriteErrorHandler(func(err error) {
+		t.Fatal("unable to write: ", err)
+	})
+
+	return service, store
+}
+
+func (store *memoryStore) Load() ([]byte, error) {
+	return store.content, nil
+}
+
+func (store *memoryStore) Save(storage *storage) error {
+	content, err := json.Marshal(storage)
+	if err != nil {
+		return err
+	}
+
+	store.content = content
+
+	return nil
+}
+
+func (store *memoryStore) Backup(path string) error {
+	return nil
+}
+
+func (store *memoryStore) Restore(path string) error {
+	return nil
+}
+
+func (store *memoryStore) Path() string {
+	return "memory"
+}
+
+func (store *memoryStore) Close() error {
+	return nil
+}
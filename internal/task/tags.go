@@ -0,0 +1,106 @@
+package task
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const tagPrefix = "#"
+
+// ParseTags splits text on commas and whitespace into a list of tags. tags
+// are lower cased, a leading # is dropped and duplicates are removed.
+func ParseTags(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	tags := []string{}
+
+	for _, field := range fields {
+		tag := strings.ToLower(strings.TrimLeft(field, tagPrefix))
+
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// GetAllTags returns every tag used by a task, sorted alphabetically
+func (service *Service) GetAllTags() []string {
+	tags := []string{}
+
+	for _, task := range service.storage.Tasks.Tasks {
+		for _, tag := range task.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	slices.Sort(tags)
+
+	return tags
+}
+
+// HasTag returns true if the task or any of its ancestors is tagged with tag,
+// subtasks are considered part of whatever their parents are tagged with.
+func (service *Service) HasTag(id int, tag string) bool {
+	tagged := service.getTask(id)
+	if tagged == nil {
+		return false
+	}
+
+	tag = strings.ToLower(strings.TrimLeft(tag, tagPrefix))
+
+	if slices.Contains(tagged.Tags, tag) {
+		return true
+	}
+
+	for _, ancestor := range service.ancestors(tagged) {
+		if slices.Contains(ancestor.Tags, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchesTag returns true if the task has the tag (see HasTag) or any task
+// below it does, which keeps the path to a tagged subtask when filtering.
+func (service *Service) MatchesTag(id int, tag string) bool {
+	if service.HasTag(id, tag) {
+		return true
+	}
+
+	for _, descendant := range service.descendants(service.getTask(id)) {
+		if service.HasTag(descendant.ID, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TagsString formats tags the way they're shown next to a task, e.g.
+// "#backend #docs"
+func TagsString(tags []string) string {
+	display := []string{}
+
+	for _, tag := range tags {
+		display = append(display, tagPrefix+tag)
+	}
+
+	return strings.Join(display, " ")
+}
+
+// tagsEditString describes the tags in a task's history
+func tagsEditString(tags []string) string {
+	if len(tags) == 0 {
+		return "none"
+	}
+
+	return TagsString(tags)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/darwinfroese/scribe/internal/database"
@@ -22,13 +23,16 @@ const (
 	SortOrderPriorityAsc
 	SortOrderPriorityDesc
 	SortOrderManual
+	SortOrderDueDate
 
 	PriorityCriticalColorKey = "critical"
 	PriorityHighColorKey     = "high"
 	PriorityMediumColorKey   = "medium"
 	PriorityLowColorKey      = "low"
 
-	SubTextColorKey = "subtext"
+	SubTextColorKey  = "subtext"
+	OverdueColorKey  = "overdue"
+	DueTodayColorKey = "duetoday"
 )
 
 // task is the internal task structure used for managing task details
@@ -36,13 +40,20 @@ const (
 // this task will be serialized and written to a file for persistence
 // the fields need to be exposed.
 type task struct {
-	ID                int       `json:"id"`
-	Completed         bool      `json:"completed"`
-	Planned           bool      `json:"planned"`
-	Priority          int       `json:"priority"`
-	InheritedPriority int       `json:"inherited_priority"`
-	Description       string    `json:"description"`
-	CompletedAt       time.Time `json:"completed_at"`
+	ID                int        `json:"id"`
+	Completed         bool       `json:"completed"`
+	Planned           bool       `json:"planned"`
+	Priority          int        `json:"priority"`
+	InheritedPriority int        `json:"inherited_priority"`
+	Description       string     `json:"description"`
+	CompletedAt       time.Time  `json:"completed_at"`
+	DeletedAt         time.Time  `json:"deleted_at"`
+	DueDate           time.Time  `json:"due_date"`
+	Tags              []string   `json:"tags"`
+	Body              string     `json:"body"`
+	History           []event    `json:"history"`
+	Intervals         []interval `json:"intervals"`
+	Pomodoros         []pomodoro `json:"pomodoros"`
 
 	SortIndex int `json:"sort_index"`
 
@@ -51,6 +62,16 @@ type task struct {
 	Children  []int `json:"children"`
 }
 
+// Details are the parts of a task that are entered by the user when adding
+// or editing a task
+type Details struct {
+	Description string
+	Priority    int
+	DueDate     time.Time
+	Tags        []string
+	Body        string
+}
+
 type taskStorage struct {
 	NextID int     `json:"next_id"`
 	Tasks  []*task `json:"tasks"`
@@ -58,102 +79,208 @@ type taskStorage struct {
 	DeletedTasks []*task `json:"deleted_tasks"`
 }
 
+// settings are preferences that are remembered between runs
+type settings struct {
+	TodoSortOrder int `json:"todo_sort_order"`
+
+	// CarryOverDate is the last day that unfinished tasks were carried over
+	// (or the carry over was skipped)
+	CarryOverDate string `json:"carry_over_date"`
+}
+
 type storage struct {
+	Version  int             `json:"version"`
 	Tasks    *taskStorage    `json:"tasks"`
 	Sessions *sessionStorage `json:"sessions"`
+	Settings settings        `json:"settings"`
 }
 
 type Service struct {
-	db *database.Database
+	store Store
 
 	storage *storage
+
+	backups  *database.Backups
+	backedUp bool
+
+	day dayBoundary
+
+	undoStack [][]byte
+	redoStack [][]byte
+
+	// writeFailed is called with the error when a change can't be written,
+	// the change is kept in memory until it's saved or reloaded over
+	writeFailed func(err error)
 }
 
-func NewService(db *database.Database) *Service {
+func NewService(store Store) *Service {
 	service := Service{
-		db: db,
+		store: store,
+		writeFailed: func(err error) {
+			log.Print("unable to write the database content: ", err)
+		},
+	}
+
+	err := service.load()
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	dbContent, err := db.Read()
+	return &service
+}
+
+// load reads the storage from the store, migrating it first if it was
+// written by an older version of scribe
+func (service *Service) load() error {
+	dbContent, err := service.store.Load()
 	if err != nil {
-		log.Fatal("unable to load the database: ", err)
+		return fmt.Errorf("unable to load the database: %w", err)
 	}
 
 	if len(dbContent) == 0 {
-		storage := &storage{}
+		storage := &storage{Version: currentVersion}
+		storage.Settings = settings{TodoSortOrder: SortOrderNone}
 		storage.Tasks = &taskStorage{NextID: 0, Tasks: make([]*task, 0)}
 		storage.Sessions = &sessionStorage{NextID: 0, Sessions: make([]*session, 0)}
 
 		service.storage = storage
-		return &service
+		return nil
+	}
+
+	version, err := documentVersion(dbContent)
+	if err != nil {
+		return fmt.Errorf("unable to parse the database contents: %w", err)
+	}
+
+	if version > currentVersion {
+		return fmt.Errorf("the database is version %d but this version of scribe only supports up to version %d, please upgrade scribe", version, currentVersion)
+	}
+
+	if version < currentVersion {
+		dbContent = service.migrate(dbContent, version)
 	}
 
 	storage := storage{}
 
 	err = json.Unmarshal(dbContent, &storage)
 	if err != nil {
-		log.Fatal("unable to parse the database contents: ", err)
+		return fmt.Errorf("unable to parse the database contents: %w", err)
 	}
 
 	service.storage = &storage
 
-	return &service
+	if version < currentVersion {
+		return service.Save()
+	}
+
+	return nil
 }
 
-func (service *Service) AddTask(description string, priority int) {
+// SetWriteErrorHandler sets what is called when a change can't be written to
+// the database, by default the error is only logged. the change is kept so
+// the handler can Save it again, Overwrite the database with it or Reload
+// the database and drop it.
+func (service *Service) SetWriteErrorHandler(handler func(err error)) {
+	service.writeFailed = handler
+}
+
+// Reload replaces the storage with what is currently in the database, any
+// changes that couldn't be written are dropped along with the undo history
+func (service *Service) Reload() error {
+	err := service.load()
+	if err != nil {
+		return err
+	}
+
+	service.undoStack = nil
+	service.redoStack = nil
+
+	return nil
+}
+
+// Overwrite writes the storage over the database even if another process
+// has changed it since it was loaded, their changes are lost
+func (service *Service) Overwrite() error {
+	_, err := service.store.Load()
+	if err != nil {
+		return fmt.Errorf("unable to load the database: %w", err)
+	}
+
+	return service.Save()
+}
+
+// migrate backs up the database as it's currently stored and then upgrades
+// its contents to the current version, the upgraded contents are returned
+// and need to be written once they've been loaded.
+func (service *Service) migrate(content []byte, version int) []byte {
+	backupPath := fmt.Sprintf("%s.v%d.bak", service.store.Path(), version)
+
+	err := service.store.Backup(backupPath)
+	if err != nil {
+		log.Fatal("unable to back up the database before migrating it: ", err)
+	}
+
+	content, err = migrate(content, version)
+	if err != nil {
+		log.Fatal("unable to migrate the database: ", err)
+	}
+
+	log.Printf(`migrated the database from version %d to %d, the original was backed up to "%s"`, version, currentVersion, backupPath)
+
+	return content
+}
+
+func (service *Service) AddTask(details Details) {
+	service.checkpoint()
+
 	ttask := task{
 		ID:                service.storage.Tasks.NextID,
-		Description:       description,
-		Priority:          priority,
-		InheritedPriority: priority,
+		Description:       details.Description,
+		Priority:          details.Priority,
+		InheritedPriority: details.Priority,
+		DueDate:           details.DueDate,
+		Tags:              details.Tags,
+		Body:              details.Body,
 		Completed:         false,
 		Planned:           false,
+		SortIndex:         service.nextSortIndex(service.topLevelTasks(false)),
 	}
 
+	service.record(&ttask, eventCreated)
+
 	service.storage.Tasks.NextID++
 	service.storage.Tasks.Tasks = append(service.storage.Tasks.Tasks, &ttask)
 
 	service.write()
 }
 
-func (service *Service) AddChildTask(description string, priority int, parentDisplay string) {
+func (service *Service) AddChildTask(details Details, parentDisplay string) {
+	service.checkpoint()
+
 	ttask := task{
 		ID:                service.storage.Tasks.NextID,
-		Description:       description,
-		Priority:          priority,
-		InheritedPriority: priority,
+		Description:       details.Description,
+		Priority:          details.Priority,
+		InheritedPriority: details.Priority,
+		DueDate:           details.DueDate,
+		Tags:              details.Tags,
+		Body:              details.Body,
 		Completed:         false,
 		Planned:           false,
 	}
 
-	parents := service.GetAllParents()
-	for _, parent := range parents {
-		display := service.FormDisplayString(parent)
-
-		if display == parentDisplay {
-			parentTask := service.getTask(parent)
-
-			ttask.Parent = parent
-			ttask.HasParent = true
-			parentTask.Children = append(parentTask.Children, ttask.ID)
-
-			if ttask.Priority < parentTask.Priority && ttask.Priority < parentTask.InheritedPriority {
-				parentTask.InheritedPriority = ttask.Priority
-			}
+	service.record(&ttask, eventCreated)
 
-			if parentTask.Planned {
-				parentTask.Planned = false
-				service.unplanTask(parentTask.ID)
-			}
+	service.storage.Tasks.NextID++
+	service.storage.Tasks.Tasks = append(service.storage.Tasks.Tasks, &ttask)
 
-			service.updateTask(parentTask)
+	for _, parent := range service.storage.Tasks.Tasks {
+		if service.FormDisplayString(parent.ID) == parentDisplay {
+			service.linkChild(parent, &ttask)
 			break
 		}
 	}
 
-	service.storage.Tasks.NextID++
-	service.storage.Tasks.Tasks = append(service.storage.Tasks.Tasks, &ttask)
-
 	service.write()
 }
 
@@ -216,6 +343,10 @@ func (service *Service) GetIncompleteTaskIDs(ordering int) []int {
 		slices.SortFunc(ids, service.sortOrderPriorityFunc(sortOrderAsc))
 	case SortOrderPriorityDesc:
 		slices.SortFunc(ids, service.sortOrderPriorityFunc(sortOrderDesc))
+	case SortOrderManual:
+		slices.SortFunc(ids, service.sortOrderManualFunc())
+	case SortOrderDueDate:
+		slices.SortFunc(ids, service.sortOrderDueDateFunc())
 	}
 
 	return ids
@@ -274,86 +405,161 @@ func (service *Service) GetChildren(id, ordering int) []int {
 		slices.SortFunc(children, service.sortOrderPriorityFunc(sortOrderAsc))
 	case SortOrderPriorityDesc:
 		slices.SortFunc(children, service.sortOrderPriorityFunc(sortOrderDesc))
+	case SortOrderManual:
+		slices.SortFunc(children, service.sortOrderManualFunc())
+	case SortOrderDueDate:
+		slices.SortFunc(children, service.sortOrderDueDateFunc())
 	}
 
 	return children
 }
 
 func (service *Service) ToggleComplete(id int) {
-	for idx, task := range service.storage.Tasks.Tasks {
-		if task.ID == id {
-			task.Completed = !task.Completed
-			task.CompletedAt = time.Now()
-
-			if !task.Completed && len(task.Children) > 0 {
-				service.resetTask(task)
-				service.write()
-				return
-			}
-
-			if task.HasParent {
-				service.completeParent(task)
-				service.adjustParentPriority(task)
-			}
-
-			if !task.Planned {
-				service.planTask(task.ID)
-				task.Planned = true
-			}
+	service.checkpoint()
+	service.toggleComplete(id)
+}
 
-			service.storage.Tasks.Tasks[idx] = task
-			service.write()
+func (service *Service) toggleComplete(id int) {
+	task := service.getTask(id)
+	if task == nil {
+		return
+	}
 
-			return
-		}
+	if task.Completed {
+		service.reopen(task)
+	} else {
+		service.complete(task)
 	}
+
+	service.write()
 }
 
 func (service *Service) AddChild(parentID, childID int) {
 	parent := service.getTask(parentID)
 	child := service.getTask(childID)
 
-	// don't allow nesting more than one level
-	if len(child.Children) > 0 {
+	if parent == nil || child == nil || parentID == childID {
 		return
 	}
 
-	parent.Children = append(parent.Children, child.ID)
-	child.Parent = parent.ID
-	child.HasParent = true
+	// a task can't be nested under one of its own descendants
+	if service.isDescendant(childID, parentID) {
+		return
+	}
 
-	service.updateTask(parent)
-	service.updateTask(child)
+	service.checkpoint()
+
+	if child.HasParent {
+		service.unlinkChild(child)
+	}
+
+	service.linkChild(parent, child)
 
 	service.write()
 }
 
 func (service *Service) RemoveChild(childID int) {
+	if !service.HasParent(childID) {
+		return
+	}
+
+	service.checkpoint()
+	service.removeChild(childID)
+}
+
+func (service *Service) removeChild(childID int) {
 	child := service.getTask(childID)
 
 	if !child.HasParent {
 		return
 	}
 
-	parent := service.getTask(child.Parent)
+	service.unlinkChild(child)
 
-	idx := slices.Index(parent.Children, child.ID)
-	parent.Children = slices.Delete(parent.Children, idx, idx+1)
-	child.Parent = 0
-	child.HasParent = false
+	service.write()
+}
 
-	if len(parent.Children) == 0 {
-		parent.InheritedPriority = priorityLow
-	} else {
-		service.adjustParentPriority(parent)
+// MoveTask moves a task up (a negative offset) or down (a positive offset)
+// among its siblings in the manual sort order
+func (service *Service) MoveTask(id, offset int) {
+	moved := service.getTask(id)
+	if moved == nil {
+		return
+	}
+
+	siblings := service.siblings(moved)
+	slices.SortFunc(siblings, func(a, b *task) int {
+		return service.sortOrderManualFunc()(a.ID, b.ID)
+	})
+
+	idx := slices.Index(siblings, moved)
+	target := idx + offset
+
+	if target < 0 || target >= len(siblings) {
+		return
+	}
+
+	service.checkpoint()
+
+	siblings = slices.Delete(siblings, idx, idx+1)
+	siblings = slices.Insert(siblings, target, moved)
+
+	for idx, sibling := range siblings {
+		sibling.SortIndex = idx
+		service.updateTask(sibling)
+	}
+
+	service.write()
+}
+
+func (service *Service) GetTodoSortOrder() int {
+	return service.storage.Settings.TodoSortOrder
+}
+
+// SetTodoSortOrder sets the sort order for the todo list and remembers it for
+// the next run, it isn't undone along with other changes
+func (service *Service) SetTodoSortOrder(ordering int) {
+	service.storage.Settings.TodoSortOrder = ordering
+	service.write()
+}
+
+// OutdentTask moves a task up one level, making it a sibling of its parent
+func (service *Service) OutdentTask(id int) {
+	child := service.getTask(id)
+
+	if child == nil || !child.HasParent {
+		return
 	}
 
-	service.updateTask(parent)
-	service.updateTask(child)
+	service.checkpoint()
+
+	parent := service.getTask(child.Parent)
+	service.unlinkChild(child)
+
+	if parent != nil && parent.HasParent {
+		grandparent := service.getTask(parent.Parent)
+		service.linkChild(grandparent, child)
+	}
 
 	service.write()
 }
 
+// GetPossibleParents returns the incomplete tasks that the task could be
+// moved under, a task can't be moved under itself or its descendants
+func (service *Service) GetPossibleParents(id int) []int {
+	ids := []int{}
+
+	for _, task := range service.storage.Tasks.Tasks {
+		if task.Completed || task.ID == id || service.isDescendant(id, task.ID) {
+			continue
+		}
+
+		ids = append(ids, task.ID)
+	}
+
+	return ids
+}
+
 func (service *Service) DeleteTask(id int) {
 	idxToDelete := 0
 	taskFound := false
@@ -371,23 +577,41 @@ func (service *Service) DeleteTask(id int) {
 		return
 	}
 
+	service.checkpoint()
+
 	task := service.storage.Tasks.Tasks[idxToDelete]
+	hasParent, parentID := task.HasParent, task.Parent
+	parent := service.getTask(task.Parent)
 
 	if task.HasParent {
-		service.RemoveChild(task.ID)
+		service.unlinkChild(task)
 	}
 
-	if len(task.Children) > 0 {
-		for _, childID := range task.Children {
-			child := service.getTask(childID)
+	// the children move up to take the deleted task's place
+	for _, childID := range task.Children {
+		child := service.getTask(childID)
+		if child == nil {
+			continue
+		}
 
-			child.HasParent = false
-			child.Parent = 0
+		child.HasParent = false
+		child.Parent = 0
+		service.recordEvent(child, event{Kind: eventUnnested, From: service.FormDisplayString(task.ID)})
+		service.updateTask(child)
 
-			service.updateTask(child)
+		if hasParent && parent != nil {
+			service.linkChild(parent, child)
 		}
 	}
 
+	// deleted tasks keep the parent and children they had when they were
+	// deleted so that they can be re-linked if they're restored
+	task.HasParent = hasParent
+	task.Parent = parentID
+	task.DeletedAt = service.now()
+	service.stopTimer(task)
+	service.record(task, eventDeleted)
+
 	service.storage.Tasks.Tasks = slices.Delete(service.storage.Tasks.Tasks, idxToDelete, idxToDelete+1)
 	service.storage.Tasks.DeletedTasks = append(service.storage.Tasks.DeletedTasks, task)
 
@@ -435,23 +659,45 @@ func (service *Service) FormDisplayString(id int) string {
 func (service *Service) DisplayString(id int) string {
 	task := service.getTask(id)
 
-	prefix := "○"
-
 	if task == nil {
 		return "unknown task"
 	}
 
+	return service.displayString(task, task.Description)
+}
+
+// displayString formats a task for the task trees, description is passed in
+// separately so that it can be decorated (e.g. highlighting search matches)
+func (service *Service) displayString(task *task, description string) string {
+	prefix := "○"
+
 	priority := min(task.Priority, task.InheritedPriority)
-	display := fmt.Sprintf("%s [%s::](%s)[white::]", task.Description, getPriorityColor(priority), getPriorityString(priority))
+	display := fmt.Sprintf("%s [%s::](%s)[white::]", description, getPriorityColor(priority), getPriorityString(priority))
+
+	if len(task.Tags) > 0 {
+		display = fmt.Sprintf("%s [%s::]%s[white::]", display, SubTextColorKey, TagsString(task.Tags))
+	}
 
-	if task.Planned && service.taskPlannedToday(task.ID) {
+	if task.Planned && service.plannedInCurrentSession(task.ID) {
 		prefix = "→"
 		display = fmt.Sprintf("[::b]%s[::B]", display)
 	}
 
+	if tracked := service.trackedTime(task, allIntervals); tracked > 0 {
+		if service.timerRunning(task) {
+			display = fmt.Sprintf("%s [%s::b]▶ %s[white::B]", display, SubTextColorKey, DurationString(tracked))
+		} else {
+			display = fmt.Sprintf("%s [%s::]%s[white::]", display, SubTextColorKey, DurationString(tracked))
+		}
+	}
+
+	if !task.Completed && !task.DueDate.IsZero() {
+		display = fmt.Sprintf("%s [%s::]due %s[white::]", display, service.getDueDateColor(task.DueDate), task.DueDate.Format(time.DateOnly))
+	}
+
 	if task.Completed {
 		prefix = "✓"
-		display = fmt.Sprintf("[::i]%s[::I] [%s::i]%s[white::I]", display, SubTextColorKey, task.CompletedAt.Format(time.DateOnly))
+		display = fmt.Sprintf("[::i]%s[::I] [%s::i]%s[white::I]", display, SubTextColorKey, service.dateOf(task.CompletedAt))
 	}
 
 	return fmt.Sprintf("%s %s", prefix, display)
@@ -464,90 +710,96 @@ func (service *Service) ReportString(id int) string {
 		return "unknown task"
 	}
 
-	// TODO: when updating this to support parent/child, use min(task.Priority, task.InheritedPriority)
-	display := fmt.Sprintf("%s (%s)", task.Description, getPriorityString(task.Priority))
-	return display
-}
+	priority := min(task.Priority, task.InheritedPriority)
+	display := fmt.Sprintf("%s (%s)", task.Description, getPriorityString(priority))
 
-func (service *Service) GetTaskDetails(id int) (string, int) {
-	task := service.getTask(id)
+	if len(task.Tags) > 0 {
+		display = fmt.Sprintf("%s %s", display, TagsString(task.Tags))
+	}
 
-	return task.Description, task.Priority
-}
+	ancestors := service.ancestors(task)
+	if len(ancestors) == 0 {
+		return display
+	}
 
-func (service *Service) EditTask(id int, description string, priority int) {
-	task := service.getTask(id)
+	path := []string{}
+	for _, ancestor := range ancestors {
+		path = append(path, ancestor.Description)
+	}
 
-	task.Description = description
-	task.Priority = priority
+	slices.Reverse(path)
 
-	// if we are childless or a parent
-	if len(task.Children) == 0 {
-		task.InheritedPriority = priority
-	} else {
-		task.InheritedPriority = min(task.InheritedPriority, priority)
-	}
+	return fmt.Sprintf("%s [%s]", display, strings.Join(path, " > "))
+}
 
-	if task.HasParent {
-		parent := service.getTask(task.Parent)
-		parent.InheritedPriority = min(parent.InheritedPriority, priority)
-		service.updateTask(parent)
-	}
+// StatusString describes where a task is at, e.g. "completed 2025-01-02"
+func (service *Service) StatusString(id int) string {
+	task := service.getTask(id)
 
-	service.updateTask(task)
-	service.write()
+	switch {
+	case task == nil:
+		return "unknown task"
+	case task.Completed:
+		return fmt.Sprintf("completed %s", service.dateOf(task.CompletedAt))
+	case task.Planned && service.plannedInCurrentSession(task.ID):
+		return "planned today"
+	default:
+		return "incomplete"
+	}
 }
 
-func (service *Service) GetTasksIDsForSession(sessionID int) []int {
-	for _, session := range service.storage.Sessions.Sessions {
-		if session.ID == sessionID {
-			return session.PlannedTasks
-		}
+// GetTaskDetails returns the details of the task, ok is false if it doesn't
+// exist (e.g. it was deleted)
+func (service *Service) GetTaskDetails(id int) (details Details, ok bool) {
+	task := service.getTask(id)
+	if task == nil {
+		return Details{}, false
 	}
 
-	return []int{}
+	return Details{
+		Description: task.Description,
+		Priority:    task.Priority,
+		DueDate:     task.DueDate,
+		Tags:        task.Tags,
+		Body:        task.Body,
+	}, true
 }
 
-func (service *Service) adjustParentPriority(pTask *task) {
-	var parent *task
+func (service *Service) EditTask(id int, details Details) {
+	service.checkpoint()
 
-	if pTask.HasParent {
-		parent = service.getTask(pTask.Parent)
-	} else {
-		parent = pTask
-	}
-
-	if parent.Completed {
-		return
-	}
+	task := service.getTask(id)
 
-	highestPriority := priorityLow
-	for _, child := range parent.Children {
-		cTask := service.getTask(child)
+	service.recordEdit(task, "description", task.Description, details.Description)
+	service.recordEdit(task, "priority", getPriorityString(task.Priority), getPriorityString(details.Priority))
+	service.recordEdit(task, "due date", dueDateString(task.DueDate), dueDateString(details.DueDate))
+	service.recordEdit(task, "tags", tagsEditString(task.Tags), tagsEditString(details.Tags))
 
-		if !cTask.Completed && cTask.Priority < highestPriority {
-			highestPriority = cTask.Priority
-		}
+	if task.Body != details.Body {
+		service.record(task, eventNotesEdited)
 	}
 
-	parent.InheritedPriority = highestPriority
+	task.Description = details.Description
+	task.Priority = details.Priority
+	task.DueDate = details.DueDate
+	task.Tags = details.Tags
+	task.Body = details.Body
+
+	service.updateInheritedPriority(task)
+	service.updateAncestorPriority(task)
 
-	service.updateTask(parent)
+	service.updateTask(task)
 	service.write()
 }
 
-func (service *Service) completeParent(task *task) {
-	parent := service.getTask(task.Parent)
-
-	for _, child := range parent.Children {
-		cTask := service.getTask(child)
-
-		if !cTask.Completed {
-			return
+func (service *Service) GetTasksIDsForSession(sessionID int) []int {
+	for _, session := range service.storage.Sessions.Sessions {
+		if session.ID == sessionID {
+			return session.PlannedTasks
 		}
 	}
 
-	service.ToggleComplete(parent.ID)
+	return []int{}
 }
 
 func (service *Service) getTask(id int) *task {
@@ -560,6 +812,18 @@ func (service *Service) getTask(id int) *task {
 	return nil
 }
 
+func (service *Service) getTasks(ids []int) []*task {
+	tasks := []*task{}
+
+	for _, id := range ids {
+		if task := service.getTask(id); task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks
+}
+
 func (service *Service) updateTask(task *task) {
 	for idx, tt := range service.storage.Tasks.Tasks {
 		if tt.ID == task.ID {
@@ -573,28 +837,77 @@ func (service *Service) resetTask(task *task) {
 	task.Planned = false
 	task.Completed = false
 
-	for _, child := range task.Children {
-		childTask := service.getTask(child)
-		childTask.Planned = false
-		childTask.Completed = false
+	for _, descendant := range service.descendants(task) {
+		if descendant.Completed {
+			service.record(descendant, eventReopened)
+		}
+
+		descendant.Planned = false
+		descendant.Completed = false
 
-		service.updateTask(childTask)
+		service.updateTask(descendant)
 	}
 
 	service.updateTask(task)
 }
 
+// EnableBackups takes a snapshot of the database before the first write
+// this service makes, keeping at most keep snapshots.
+func (service *Service) EnableBackups(keep int) {
+	if keep <= 0 {
+		return
+	}
+
+	backups, err := database.NewBackups(service.store.Path(), keep)
+	if err != nil {
+		log.Fatal("unable to create the backups folder: ", err)
+	}
+
+	service.backups = backups
+}
+
+func (service *Service) Close() {
+	err := service.store.Close()
+	if err != nil {
+		log.Print("unable to close the database: ", err)
+	}
+}
+
 func (service *Service) write() {
-	// NOTE: should this hard exit here?
-	content, err := json.Marshal(service.storage)
+	err := service.Save()
+	if err != nil {
+		service.writeFailed(err)
+	}
+}
+
+// Save writes the storage to the database, taking a backup first if this is
+// the first write and backups are enabled. it fails with
+// database.ErrModified if another process has changed the database.
+func (service *Service) Save() error {
+	if service.backups != nil && !service.backedUp {
+		err := service.backup()
+		if err != nil {
+			return err
+		}
+	}
+
+	return service.store.Save(service.storage)
+}
+
+func (service *Service) backup() error {
+	err := service.store.Backup(service.backups.NextPath())
 	if err != nil {
-		log.Fatal("unable to marshal the database content: ", err)
+		return fmt.Errorf("unable to back up the database: %w", err)
 	}
 
-	err = service.db.Write(content)
+	service.backedUp = true
+
+	err = service.backups.Prune()
 	if err != nil {
-		log.Fatal("unable to write the database content: ", err)
+		return fmt.Errorf("unable to remove old database backups: %w", err)
 	}
+
+	return nil
 }
 
 func getPriorityString(priority int) string {
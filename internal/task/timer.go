@@ -0,0 +1,148 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// interval is a stretch of time spent on a task during a session, End is the
+// zero time while the timer is running
+type interval struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Session int       `json:"session"`
+}
+
+// ToggleTimer starts timing the task in the current session, stopping any
+// other timer first, or stops the task's timer if it's already running
+func (service *Service) ToggleTimer(id int) {
+	task := service.getTask(id)
+	if task == nil {
+		return
+	}
+
+	service.checkpoint()
+
+	if service.timerRunning(task) {
+		service.stopTimer(task)
+	} else {
+		service.stopTimers()
+
+		session := service.getOrCreateCurrentSession()
+		task.Intervals = append(task.Intervals, interval{Start: service.now(), Session: session.ID})
+		service.updateTask(task)
+	}
+
+	service.write()
+}
+
+// GetTimedTaskID returns the task whose timer is running, if there is one
+func (service *Service) GetTimedTaskID() (int, bool) {
+	for _, task := range service.storage.Tasks.Tasks {
+		if service.timerRunning(task) {
+			return task.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+// TrackedTime returns the time spent on the task and everything below it
+func (service *Service) TrackedTime(id int) time.Duration {
+	return service.trackedTime(service.getTask(id), allIntervals)
+}
+
+// SessionTrackedTime returns the time spent on the task and everything below
+// it during the session
+func (service *Service) SessionTrackedTime(sessionID, taskID int) time.Duration {
+	return service.trackedTime(service.getTask(taskID), func(interval interval) bool {
+		return interval.Session == sessionID
+	})
+}
+
+// SessionTotalTime returns the time spent on every task during the session,
+// including tasks that have since been deleted
+func (service *Service) SessionTotalTime(id int) time.Duration {
+	var total time.Duration
+
+	for _, tasks := range [][]*task{service.storage.Tasks.Tasks, service.storage.Tasks.DeletedTasks} {
+		for _, task := range tasks {
+			for _, interval := range task.Intervals {
+				if interval.Session == id {
+					total += service.intervalLength(interval)
+				}
+			}
+		}
+	}
+
+	return total
+}
+
+// DurationString formats a duration as hours, minutes and seconds, e.g.
+// "1:05:00"
+func DurationString(duration time.Duration) string {
+	seconds := int(duration.Round(time.Second).Seconds())
+
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+func allIntervals(interval) bool {
+	return true
+}
+
+func (service *Service) trackedTime(root *task, include func(interval) bool) time.Duration {
+	var total time.Duration
+
+	if root == nil {
+		return total
+	}
+
+	for _, tracked := range append([]*task{root}, service.descendants(root)...) {
+		for _, interval := range tracked.Intervals {
+			if include(interval) {
+				total += service.intervalLength(interval)
+			}
+		}
+	}
+
+	return total
+}
+
+// ownTrackedTime returns the time spent on the task without its subtasks
+func (service *Service) ownTrackedTime(task *task) time.Duration {
+	var total time.Duration
+
+	for _, interval := range task.Intervals {
+		total += service.intervalLength(interval)
+	}
+
+	return total
+}
+
+func (service *Service) intervalLength(interval interval) time.Duration {
+	if interval.End.IsZero() {
+		return time.Since(interval.Start)
+	}
+
+	return interval.End.Sub(interval.Start)
+}
+
+func (service *Service) timerRunning(task *task) bool {
+	return len(task.Intervals) > 0 && task.Intervals[len(task.Intervals)-1].End.IsZero()
+}
+
+func (service *Service) stopTimer(task *task) {
+	if !service.timerRunning(task) {
+		return
+	}
+
+	task.Intervals[len(task.Intervals)-1].End = service.now()
+	service.updateTask(task)
+}
+
+// stopTimers stops the timer of every task
+func (service *Service) stopTimers() {
+	for _, task := range service.storage.Tasks.Tasks {
+		service.stopTimer(task)
+	}
+}
@@ -0,0 +1,107 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToggleTimer(t *testing.T) {
+	service, _ := newTestService(t)
+
+	service.AddTask(Details{Description: "first"})
+	service.AddTask(Details{Description: "second"})
+
+	if _, ok := service.GetTimedTaskID(); ok {
+		t.Fatal("expected no timer to be running")
+	}
+
+	service.ToggleTimer(0)
+
+	if id, ok := service.GetTimedTaskID(); !ok || id != 0 {
+		t.Fatalf("expected the first task to be timed, got %d (%t)", id, ok)
+	}
+
+	session, ok := service.GetCurrentSessionID()
+	if !ok {
+		t.Fatal("expected a session to be started for the timer")
+	}
+
+	// only one timer runs at a time
+	service.ToggleTimer(1)
+
+	if id, ok := service.GetTimedTaskID(); !ok || id != 1 {
+		t.Fatalf("expected the second task to be timed, got %d (%t)", id, ok)
+	}
+
+	first := service.getTask(0)
+	if len(first.Intervals) != 1 || first.Intervals[0].End.IsZero() || first.Intervals[0].Session != session {
+		t.Fatalf("expected the first task's timer to be stopped, got %+v", first.Intervals)
+	}
+
+	service.ToggleTimer(1)
+
+	if id, ok := service.GetTimedTaskID(); ok {
+		t.Fatalf("expected no timer to be running, got %d", id)
+	}
+}
+
+func TestTrackedTime(t *testing.T) {
+	service, _ := newTestService(t)
+
+	service.AddTask(Details{Description: "parent"})
+	service.AddTask(Details{Description: "child"})
+	service.AddTask(Details{Description: "deleted"})
+	service.AddChild(0, 1)
+
+	start := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	track := func(id, session, minutes int) {
+		task := service.getTask(id)
+		task.Intervals = append(task.Intervals, interval{
+			Start:   start,
+			End:     start.Add(time.Duration(minutes) * time.Minute),
+			Session: session,
+		})
+	}
+
+	track(0, 0, 30)
+	track(1, 0, 15)
+	track(1, 1, 45)
+	track(2, 0, 60)
+
+	service.DeleteTask(2)
+
+	tests := []struct {
+		name     string
+		got      time.Duration
+		expected time.Duration
+	}{
+		{name: "task and subtasks", got: service.TrackedTime(0), expected: 90 * time.Minute},
+		{name: "subtask", got: service.TrackedTime(1), expected: time.Hour},
+		{name: "task in session", got: service.SessionTrackedTime(0, 0), expected: 45 * time.Minute},
+		{name: "session including deleted tasks", got: service.SessionTotalTime(0), expected: 105 * time.Minute},
+		{name: "missing task", got: service.TrackedTime(5), expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.got != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, test.got)
+			}
+		})
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                                     "0:00:00",
+		59*time.Second + 600*time.Millisecond: "0:01:00",
+		65 * time.Minute:                      "1:05:00",
+		26*time.Hour + 3*time.Second:          "26:00:03",
+	}
+
+	for duration, expected := range tests {
+		if got := DurationString(duration); got != expected {
+			t.Errorf("expected %v to be %q, got %q", duration, expected, got)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// GetDeletedTaskIDs returns the deleted tasks, most recently deleted first
+func (service *Service) GetDeletedTaskIDs() []int {
+	ids := []int{}
+
+	for _, task := range service.storage.Tasks.DeletedTasks {
+		ids = append(ids, task.ID)
+	}
+
+	slices.Reverse(ids)
+
+	return ids
+}
+
+func (service *Service) TrashString(id int) string {
+	task := service.getDeletedTask(id)
+
+	if task == nil {
+		return "unknown task"
+	}
+
+	deletedAt := "unknown"
+	if !task.DeletedAt.IsZero() {
+		deletedAt = task.DeletedAt.Format(time.DateTime)
+	}
+
+	return fmt.Sprintf("%s (%s) deleted %s", task.Description, getPriorityString(task.Priority), deletedAt)
+}
+
+// RestoreTask moves a deleted task back into the task list, it's re-linked to
+// its parent and any of its children that still exist and haven't been moved.
+// it returns false if there's no deleted task with id.
+func (service *Service) RestoreTask(id int) bool {
+	idx := slices.IndexFunc(service.storage.Tasks.DeletedTasks, func(task *task) bool {
+		return task.ID == id
+	})
+
+	if idx == -1 {
+		return false
+	}
+
+	service.checkpoint()
+
+	task := service.storage.Tasks.DeletedTasks[idx]
+	service.storage.Tasks.DeletedTasks = slices.Delete(service.storage.Tasks.DeletedTasks, idx, idx+1)
+
+	hasParent, parentID, children := task.HasParent, task.Parent, task.Children
+
+	task.DeletedAt = time.Time{}
+	service.record(task, eventRestored)
+	task.Planned = false
+	task.HasParent = false
+	task.Parent = 0
+	task.Children = []int{}
+	service.storage.Tasks.Tasks = append(service.storage.Tasks.Tasks, task)
+
+	parent := service.getTask(parentID)
+	if hasParent && parent != nil {
+		service.linkChild(parent, task)
+	}
+
+	// when the task was deleted its children moved up to its parent, only
+	// the ones that haven't been moved since are brought back under it
+	for _, childID := range children {
+		child := service.getTask(childID)
+		if child == nil || child.HasParent != hasParent || child.Parent != parentID {
+			continue
+		}
+
+		if child.HasParent {
+			service.unlinkChild(child)
+		}
+
+		service.linkChild(task, child)
+	}
+
+	service.updateInheritedPriority(task)
+	service.updateAncestorPriority(task)
+
+	service.write()
+
+	return true
+}
+
+// PurgeTask permanently removes a deleted task, it returns false if there's no
+// deleted task with id.
+func (service *Service) PurgeTask(id int) bool {
+	idx := slices.IndexFunc(service.storage.Tasks.DeletedTasks, func(task *task) bool {
+		return task.ID == id
+	})
+
+	if idx == -1 {
+		return false
+	}
+
+	service.checkpoint()
+
+	service.storage.Tasks.DeletedTasks = slices.Delete(service.storage.Tasks.DeletedTasks, idx, idx+1)
+	service.write()
+
+	return true
+}
+
+// PurgeTrash permanently removes every deleted task and returns how many were removed
+func (service *Service) PurgeTrash() int {
+	count := len(service.storage.Tasks.DeletedTasks)
+	if count == 0 {
+		return 0
+	}
+
+	service.checkpoint()
+
+	service.storage.Tasks.DeletedTasks = []*task{}
+	service.write()
+
+	return count
+}
+
+func (service *Service) getDeletedTask(id int) *task {
+	for _, task := range service.storage.Tasks.DeletedTasks {
+		if task.ID == id {
+			return task
+		}
+	}
+
+	return nil
+}
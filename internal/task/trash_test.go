@@ -0,0 +1,99 @@
+package task
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDeleteAndRestoreTask(t *testing.T) {
+	service, _ := newTestService(t)
+
+	service.AddTask(Details{Description: "parent"})
+	service.AddTask(Details{Description: "middle"})
+	service.AddTask(Details{Description: "child"})
+	service.AddChild(0, 1)
+	service.AddChild(1, 2)
+
+	service.DeleteTask(1)
+
+	if ids := service.GetDeletedTaskIDs(); !slices.Equal(ids, []int{1}) {
+		t.Fatalf("expected the task to be in the trash, found %v", ids)
+	}
+
+	if parent := service.GetParent(2); parent != 0 {
+		t.Fatalf("expected the child to move up to the deleted task's parent, its parent is %d", parent)
+	}
+
+	if _, ok := service.GetTaskDetails(1); ok {
+		t.Fatal("expected no details for a task in the trash")
+	}
+
+	if !service.RestoreTask(1) {
+		t.Fatal("expected the task to be restored")
+	}
+
+	if len(service.GetDeletedTaskIDs()) != 0 {
+		t.Error("expected the trash to be empty after restoring")
+	}
+
+	if parent := service.GetParent(1); parent != 0 {
+		t.Errorf("expected the restored task to be back under its parent, its parent is %d", parent)
+	}
+
+	if parent := service.GetParent(2); parent != 1 {
+		t.Errorf("expected the child to be back under the restored task, its parent is %d", parent)
+	}
+
+	if details, ok := service.GetTaskDetails(1); !ok || details.Description != "middle" {
+		t.Errorf("expected the restored task's details, got %+v (%t)", details, ok)
+	}
+
+	if service.RestoreTask(1) {
+		t.Error("a task that isn't in the trash can't be restored")
+	}
+}
+
+func TestRestoreKeepsChildrenThatWereMoved(t *testing.T) {
+	service, _ := newTestService(t)
+
+	service.AddTask(Details{Description: "deleted"})
+	service.AddTask(Details{Description: "child"})
+	service.AddTask(Details{Description: "other"})
+	service.AddChild(0, 1)
+
+	service.DeleteTask(0)
+	service.AddChild(2, 1)
+	service.RestoreTask(0)
+
+	if parent := service.GetParent(1); parent != 2 {
+		t.Errorf("expected the child to stay where it was moved, its parent is %d", parent)
+	}
+}
+
+func TestPurgeTasks(t *testing.T) {
+	service, _ := newTestService(t)
+
+	for _, description := range []string{"first", "second", "third"} {
+		service.AddTask(Details{Description: description})
+	}
+
+	service.DeleteTask(0)
+	service.DeleteTask(1)
+	service.DeleteTask(2)
+
+	if !service.PurgeTask(1) || service.PurgeTask(1) {
+		t.Fatal("expected the task to be purged once")
+	}
+
+	if ids := service.GetDeletedTaskIDs(); !slices.Equal(ids, []int{2, 0}) {
+		t.Fatalf("expected the other tasks to be left in the trash, found %v", ids)
+	}
+
+	if count := service.PurgeTrash(); count != 2 {
+		t.Errorf("expected 2 tasks to be purged, %d were", count)
+	}
+
+	if service.RestoreTask(0) {
+		t.Error("a purged task can't be restored")
+	}
+}
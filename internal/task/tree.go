@@ -0,0 +1,290 @@
+package task
+
+import (
+	"slices"
+	"time"
+)
+
+// linkChild makes child a child of parent, child must not already have a
+// parent. the inherited priority and planned state of every ancestor is
+// updated to include the child.
+func (service *Service) linkChild(parent, child *task) {
+	child.SortIndex = service.nextSortIndex(service.getTasks(parent.Children))
+
+	parent.Children = append(parent.Children, child.ID)
+	child.Parent = parent.ID
+	child.HasParent = true
+	service.recordEvent(child, event{Kind: eventNested, To: service.FormDisplayString(parent.ID)})
+
+	service.updateTask(parent)
+	service.updateTask(child)
+
+	service.updateAncestorPriority(child)
+	service.planParent(parent.ID)
+}
+
+// unlinkChild removes child from its parent, the old parent's inherited
+// priority and planned state are updated to no longer include the child.
+func (service *Service) unlinkChild(child *task) {
+	parent := service.getTask(child.Parent)
+
+	child.Parent = 0
+	child.HasParent = false
+	child.SortIndex = service.nextSortIndex(service.topLevelTasks(child.Completed))
+
+	if parent != nil {
+		service.recordEvent(child, event{Kind: eventUnnested, From: service.FormDisplayString(parent.ID)})
+	}
+
+	service.updateTask(child)
+
+	if parent == nil {
+		return
+	}
+
+	idx := slices.Index(parent.Children, child.ID)
+	if idx != -1 {
+		parent.Children = slices.Delete(parent.Children, idx, idx+1)
+	}
+
+	service.updateTask(parent)
+
+	service.updateInheritedPriority(parent)
+	service.updateAncestorPriority(parent)
+	service.planParent(parent.ID)
+}
+
+// siblings returns the tasks that share a parent with task, including task.
+// for top level tasks these are the other top level tasks in the same list
+// (completed or not) as task.
+func (service *Service) siblings(task *task) []*task {
+	if task.HasParent {
+		parent := service.getTask(task.Parent)
+		if parent != nil {
+			return service.getTasks(parent.Children)
+		}
+	}
+
+	return service.topLevelTasks(task.Completed)
+}
+
+func (service *Service) topLevelTasks(completed bool) []*task {
+	tasks := []*task{}
+
+	for _, task := range service.storage.Tasks.Tasks {
+		if !task.HasParent && task.Completed == completed {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks
+}
+
+// nextSortIndex returns a sort index that places a task after all of tasks
+func (service *Service) nextSortIndex(tasks []*task) int {
+	next := 0
+
+	for _, task := range tasks {
+		next = max(next, task.SortIndex+1)
+	}
+
+	return next
+}
+
+// isDescendant returns true if id is anywhere below ancestorID
+func (service *Service) isDescendant(ancestorID, id int) bool {
+	for _, descendant := range service.descendants(service.getTask(ancestorID)) {
+		if descendant.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// descendants returns every task below root, depth first
+func (service *Service) descendants(root *task) []*task {
+	descendants := []*task{}
+
+	if root == nil {
+		return descendants
+	}
+
+	for _, childID := range root.Children {
+		child := service.getTask(childID)
+		if child == nil {
+			continue
+		}
+
+		descendants = append(descendants, child)
+		descendants = append(descendants, service.descendants(child)...)
+	}
+
+	return descendants
+}
+
+// ancestors returns every task above child, starting with its parent
+func (service *Service) ancestors(child *task) []*task {
+	ancestors := []*task{}
+
+	for child != nil && child.HasParent {
+		child = service.getTask(child.Parent)
+		if child == nil {
+			break
+		}
+
+		ancestors = append(ancestors, child)
+	}
+
+	return ancestors
+}
+
+// updateInheritedPriority sets the inherited priority of task to the highest
+// priority of itself and any of its incomplete children
+func (service *Service) updateInheritedPriority(task *task) {
+	inherited := task.Priority
+
+	for _, childID := range task.Children {
+		child := service.getTask(childID)
+
+		if child != nil && !child.Completed {
+			inherited = min(inherited, child.Priority, child.InheritedPriority)
+		}
+	}
+
+	task.InheritedPriority = inherited
+	service.updateTask(task)
+}
+
+// updateAncestorPriority updates the inherited priority of every ancestor of
+// task, starting with its parent
+func (service *Service) updateAncestorPriority(task *task) {
+	for _, ancestor := range service.ancestors(task) {
+		service.updateInheritedPriority(ancestor)
+	}
+}
+
+// complete completes task along with everything below it, the task's parent
+// is completed as well once all of its children are done
+func (service *Service) complete(task *task) {
+	now := service.now()
+
+	for _, descendant := range service.descendants(task) {
+		if !descendant.Completed {
+			service.markCompleted(descendant, now)
+		}
+	}
+
+	service.markCompleted(task, now)
+	service.updateAncestorPriority(task)
+
+	service.completeParent(task, now)
+}
+
+func (service *Service) markCompleted(task *task, now time.Time) {
+	task.Completed = true
+	task.CompletedAt = now
+	service.stopTimer(task)
+	service.record(task, eventCompleted)
+
+	if !task.Planned {
+		service.planTask(task.ID)
+		task.Planned = true
+	}
+
+	service.updateTask(task)
+}
+
+func (service *Service) completeParent(task *task, now time.Time) {
+	parent := service.getTask(task.Parent)
+	if !task.HasParent || parent == nil || parent.Completed {
+		return
+	}
+
+	for _, child := range parent.Children {
+		cTask := service.getTask(child)
+
+		if cTask != nil && !cTask.Completed {
+			return
+		}
+	}
+
+	service.markCompleted(parent, now)
+	service.updateAncestorPriority(parent)
+
+	service.completeParent(parent, now)
+}
+
+// reopen un-completes task, a task with children is reset along with
+// everything below it and any completed ancestors are reopened since they
+// now have unfinished work
+func (service *Service) reopen(task *task) {
+	task.Completed = false
+	service.record(task, eventReopened)
+
+	if len(task.Children) > 0 {
+		service.resetTask(task)
+	}
+
+	for _, ancestor := range service.ancestors(task) {
+		if ancestor.Completed {
+			ancestor.Completed = false
+			service.record(ancestor, eventReopened)
+			service.updateTask(ancestor)
+		}
+	}
+
+	service.updateInheritedPriority(task)
+	service.updateAncestorPriority(task)
+}
+
+// planDescendants sets the planned state of task and every incomplete task
+// below it
+func (service *Service) planDescendants(planned bool, task *task) {
+	service.setPlanned(task, planned)
+
+	for _, descendant := range service.descendants(task) {
+		if descendant.Completed || descendant.Planned == planned {
+			continue
+		}
+
+		service.setPlanned(descendant, planned)
+	}
+}
+
+// planParent plans the parent when all of its children are planned (or
+// completed) and unplans it otherwise, working up through every ancestor
+func (service *Service) planParent(parentID int) {
+	parent := service.getTask(parentID)
+
+	if parent == nil || len(parent.Children) == 0 || parent.Completed {
+		return
+	}
+
+	parentPlanned := true
+	for _, childID := range parent.Children {
+		child := service.getTask(childID)
+		if child != nil && !child.Planned && !child.Completed {
+			parentPlanned = false
+			break
+		}
+	}
+
+	service.setPlanned(parent, parentPlanned)
+
+	if parent.HasParent {
+		service.planParent(parent.Parent)
+	}
+}
+
+func (service *Service) setPlanned(task *task, planned bool) {
+	task.Planned = planned
+
+	if planned {
+		service.planTask(task.ID)
+	} else {
+		service.unplanTask(task.ID)
+	}
+
+	service.updateTask(task)
+}
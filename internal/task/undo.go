@@ -0,0 +1,127 @@
+package task
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+)
+
+const (
+	// maxUndoHistory is the number of changes that can be undone
+	maxUndoHistory = 100
+)
+
+// checkpoint records the current storage so that the change about to be made
+// can be undone, it needs to be called once at the start of every mutation
+// except for the ones kept by keepUnrecorded.
+func (service *Service) checkpoint() {
+	service.undoStack = append(service.undoStack, service.snapshot())
+	service.redoStack = nil
+
+	if len(service.undoStack) > maxUndoHistory {
+		service.undoStack = service.undoStack[1:]
+	}
+}
+
+// Undo restores the storage to what it was before the last change, it
+// returns false if there is nothing to undo.
+func (service *Service) Undo() bool {
+	if len(service.undoStack) == 0 {
+		return false
+	}
+
+	previous := service.undoStack[len(service.undoStack)-1]
+	service.undoStack = service.undoStack[:len(service.undoStack)-1]
+	service.redoStack = append(service.redoStack, service.snapshot())
+
+	service.restore(previous)
+	service.write()
+
+	return true
+}
+
+// Redo re-applies the last change that was undone, it returns false if there
+// is nothing to redo.
+func (service *Service) Redo() bool {
+	if len(service.redoStack) == 0 {
+		return false
+	}
+
+	next := service.redoStack[len(service.redoStack)-1]
+	service.redoStack = service.redoStack[:len(service.redoStack)-1]
+	service.undoStack = append(service.undoStack, service.snapshot())
+
+	service.restore(next)
+	service.write()
+
+	return true
+}
+
+func (service *Service) snapshot() []byte {
+	content, err := json.Marshal(service.storage)
+	if err != nil {
+		log.Fatal("unable to snapshot the database content: ", err)
+	}
+
+	return content
+}
+
+// restore replaces the storage with a snapshot, keeping the changes that
+// aren't checkpointed (see keepUnrecorded)
+func (service *Service) restore(content []byte) {
+	storage := storage{}
+
+	err := json.Unmarshal(content, &storage)
+	if err != nil {
+		log.Fatal("unable to restore the database content: ", err)
+	}
+
+	service.keepUnrecorded(&storage)
+	service.storage = &storage
+}
+
+// keepUnrecorded copies what isn't part of the undo history from the current
+// storage into restored: the settings, the focus blocks logged against each
+// task and any session that was started to log a focus block in.
+func (service *Service) keepUnrecorded(restored *storage) {
+	restored.Settings = service.storage.Settings
+
+	current := map[int]*task{}
+	for _, task := range slices.Concat(service.storage.Tasks.Tasks, service.storage.Tasks.DeletedTasks) {
+		current[task.ID] = task
+	}
+
+	sessions := map[int]bool{}
+	for _, session := range restored.Sessions.Sessions {
+		sessions[session.ID] = true
+	}
+
+	added := false
+
+	for _, task := range slices.Concat(restored.Tasks.Tasks, restored.Tasks.DeletedTasks) {
+		kept, ok := current[task.ID]
+		if !ok {
+			continue
+		}
+
+		task.Pomodoros = kept.Pomodoros
+
+		for _, pomodoro := range task.Pomodoros {
+			session := service.getSession(pomodoro.Session)
+			if session == nil || sessions[session.ID] {
+				continue
+			}
+
+			restored.Sessions.Sessions = append(restored.Sessions.Sessions, session)
+			restored.Sessions.NextID = max(restored.Sessions.NextID, session.ID+1)
+			sessions[session.ID] = true
+			added = true
+		}
+	}
+
+	if added {
+		slices.SortFunc(restored.Sessions.Sessions, func(a, b *session) int {
+			return a.ID - b.ID
+		})
+	}
+}
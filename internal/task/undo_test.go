@@ -0,0 +1,151 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUndoAndRedo(t *testing.T) {
+	service, store := newTestService(t)
+
+	if service.Undo() || service.Redo() {
+		t.Fatal("there shouldn't be anything to undo or redo")
+	}
+
+	service.AddTask(Details{Description: "first"})
+	service.AddTask(Details{Description: "second"})
+
+	if !service.Undo() {
+		t.Fatal("expected to undo adding the second task")
+	}
+
+	if service.Count() != 1 {
+		t.Fatalf("expected 1 task after undoing, got %d", service.Count())
+	}
+
+	if stored := NewService(store); stored.Count() != 1 {
+		t.Fatalf("expected the undo to be written, %d tasks are stored", stored.Count())
+	}
+
+	if !service.Redo() || service.Count() != 2 {
+		t.Fatalf("expected 2 tasks after redoing, got %d", service.Count())
+	}
+
+	if service.Redo() {
+		t.Fatal("there shouldn't be anything left to redo")
+	}
+}
+
+func TestChangeClearsRedo(t *testing.T) {
+	service, _ := newTestService(t)
+
+	service.AddTask(Details{Description: "first"})
+	service.Undo()
+	service.AddTask(Details{Description: "second"})
+
+	if service.Redo() {
+		t.Fatal("a new change should clear what can be redone")
+	}
+
+	if details, _ := service.GetTaskDetails(service.GetAllTaskIDs()[0]); details.Description != "second" {
+		t.Errorf("expected the second task to be kept, got %q", details.Description)
+	}
+}
+
+func TestUndoHistoryIsLimited(t *testing.T) {
+	service, _ := newTestService(t)
+
+	for range maxUndoHistory + 5 {
+		service.AddTask(Details{Description: "task"})
+	}
+
+	undone := 0
+	for service.Undo() {
+		undone++
+	}
+
+	if undone != maxUndoHistory {
+		t.Errorf("undid %d changes, expected %d", undone, maxUndoHistory)
+	}
+
+	if service.Count() != 5 {
+		t.Errorf("expected the first 5 tasks to be left, got %d", service.Count())
+	}
+}
+
+func TestUndoKeepsPomodoros(t *testing.T) {
+	service, store := newTestService(t)
+
+	service.AddTask(Details{Description: "task"})
+	service.EditTask(0, Details{Description: "edited"})
+	service.LogPomodoro(0, 25*time.Minute)
+
+	if !service.Undo() {
+		t.Fatal("expected to undo the edit")
+	}
+
+	if details, _ := service.GetTaskDetails(0); details.Description != "task" {
+		t.Errorf("expected the edit to be undone, the description is %q", details.Description)
+	}
+
+	if count := service.PomodoroCount(0); count != 1 {
+		t.Errorf("expected the focus block to be kept, %d were logged", count)
+	}
+
+	if count := NewService(store).PomodoroCount(0); count != 1 {
+		t.Errorf("expected the focus block to be written, %d are stored", count)
+	}
+
+	if !service.Redo() || service.PomodoroCount(0) != 1 {
+		t.Errorf("expected the focus block to be kept after redoing, %d were logged", service.PomodoroCount(0))
+	}
+}
+
+func TestUndoKeepsSessionsStartedForPomodoros(t *testing.T) {
+	service, _ := newTestService(t)
+
+	service.AddTask(Details{Description: "task"})
+	service.EditTask(0, Details{Description: "edited"})
+	service.LogPomodoro(0, 25*time.Minute)
+
+	session, ok := service.GetCurrentSessionID()
+	if !ok {
+		t.Fatal("expected logging a focus block to start a session")
+	}
+
+	service.Undo()
+
+	if current, ok := service.GetCurrentSessionID(); !ok || current != session {
+		t.Fatalf("expected session %d to be kept, the current session is %d", session, current)
+	}
+
+	if count := service.SessionPomodoroCount(session, 0); count != 1 {
+		t.Errorf("expected the focus block to be in the session, found %d", count)
+	}
+
+	if started := service.StartSession("next"); started == session {
+		t.Errorf("the id of the kept session %d was reused", session)
+	}
+}
+
+func TestUndoKeepsSettings(t *testing.T) {
+	service, _ := newTestService(t)
+
+	service.AddTask(Details{Description: "task"})
+	service.SetTodoSortOrder(SortOrderPriorityDesc)
+	service.SkipCarryOver()
+
+	service.Undo()
+
+	if service.Count() != 0 {
+		t.Fatalf("expected adding the task to be undone, there are %d tasks", service.Count())
+	}
+
+	if order := service.GetTodoSortOrder(); order != SortOrderPriorityDesc {
+		t.Errorf("expected the sort order to be kept, it's %d", order)
+	}
+
+	if date := service.storage.Settings.CarryOverDate; date != service.Today() {
+		t.Errorf("expected the skipped carry over to be kept, the date is %q", date)
+	}
+}
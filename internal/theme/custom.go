@@ -12,5 +12,7 @@ func custom() *Theme {
 		PriorityMedium:   "#000000",
 		PriorityHigh:     "#000000",
 		PriorityCritical: "#000000",
+		Overdue:          "#000000",
+		DueToday:         "#000000",
 	}
 }
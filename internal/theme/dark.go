@@ -14,5 +14,7 @@ func dark() *Theme {
 		PriorityMedium:   "#FF9F00",
 		PriorityHigh:     "#F4631E",
 		PriorityCritical: "#CB0404",
+		Overdue:          "#FF4C4C",
+		DueToday:         "#FFD65A",
 	}
 }
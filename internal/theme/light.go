@@ -14,5 +14,7 @@ func light() *Theme {
 		PriorityMedium:   "#F4AC45",
 		PriorityHigh:     "#FD6035",
 		PriorityCritical: "#A61C3C",
+		Overdue:          "#C0392B",
+		DueToday:         "#D68910",
 	}
 }
@@ -14,5 +14,7 @@ func prairie() *Theme {
 		PriorityMedium:   "#3D7F2E",
 		PriorityHigh:     "#AFA53C",
 		PriorityCritical: "#FF3336",
+		Overdue:          "#FF3336",
+		DueToday:         "#AFA53C",
 	}
 }
@@ -35,6 +35,9 @@ type Theme struct {
 	PriorityMedium   string
 	PriorityHigh     string
 	PriorityCritical string
+
+	Overdue  string
+	DueToday string
 }
 
 func Load(theme *Theme) *Theme {
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/darwinfroese/scribe/internal/theme"
+)
+
+const (
+	carryOverButton = "Carry Over"
+	skipButton      = "Skip"
+)
+
+// PromptCarryOver asks whether the unfinished tasks of the previous session
+// should be carried over into today's session when the application starts. it
+// does nothing if there's nothing to carry over.
+func (ui *UI) PromptCarryOver(move bool) {
+	count := len(ui.taskService.GetCarryOverTaskIDs())
+	if count == 0 {
+		return
+	}
+
+	prompt := tview.NewModal().
+		SetText(fmt.Sprintf("%d unfinished task(s) were planned on %s.\nCarry them over into today's session?",
+			count, ui.taskService.GetPreviousSessionDate())).
+		AddButtons([]string{carryOverButton, skipButton}).
+		SetBackgroundColor(theme.Color(ui.theme.Background)).
+		SetTextColor(theme.Color(ui.theme.Text)).
+		SetButtonStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background))).
+		SetButtonActivatedStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
+
+	prompt.SetDoneFunc(func(index int, label string) {
+		if label == carryOverButton {
+			ui.taskService.CarryOver(move)
+		} else {
+			ui.taskService.SkipCarryOver()
+		}
+
+		ui.pages.RemovePage(carryOverPageName)
+		ui.hideForm(carryOverPageName)
+		ui.refresh()
+	})
+
+	ui.pages.AddPage(carryOverPageName, prompt, true, true)
+	ui.app.SetFocus(prompt)
+
+	ui.formOpen = true
+}
@@ -2,7 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	Task "github.com/darwinfroese/scribe/internal/task"
 	"github.com/darwinfroese/scribe/internal/theme"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -23,44 +26,136 @@ func (ui *UI) createForm(action string, name string, childForm bool, actionHandl
 	}
 
 	if childForm {
-		parent := tview.NewDropDown().SetLabel("Parent:").SetOptions([]string{}, nil)
+		parent := ui.createDropDown("Parent:", []string{})
+		form.AddFormItem(parent)
+	}
 
-		parent.SetFocusedStyle(
-			tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).
-				Background(theme.Color(ui.theme.BackgroundFocus)),
-		)
+	taskInput := tview.NewInputField().SetLabel("Task:").SetFieldWidth(80)
+	form.AddFormItem(taskInput)
 
-		parent.SetListStyles(
-			// unselected
-			tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)),
-			// selected
-			tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
+	dropDown := ui.createDropDown("Priority:", []string{"Critical", "High", "Medium", "Low"})
+	form.AddFormItem(dropDown)
 
-		parent.SetPrefixStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)))
+	dueInput := tview.NewInputField().
+		SetLabel("Due:").
+		SetFieldWidth(len(time.DateOnly) + 1).
+		SetPlaceholder("YYYY-MM-DD").
+		SetAcceptanceFunc(tview.InputFieldMaxLength(len(time.DateOnly)))
+	dueInput.SetPlaceholderStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.SubText)).Background(theme.Color(ui.theme.InputBackground)))
+	form.AddFormItem(dueInput)
+
+	tagsInput := tview.NewInputField().
+		SetLabel("Tags:").
+		SetFieldWidth(80).
+		SetPlaceholder("backend, docs")
+	tagsInput.SetPlaceholderStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.SubText)).Background(theme.Color(ui.theme.InputBackground)))
+	form.AddFormItem(tagsInput)
+
+	bodyInput := tview.NewTextArea().
+		SetLabel("Notes:").
+		SetSize(taskBodyHeight, 80).
+		SetPlaceholder("longer notes about the task")
+	bodyInput.SetPlaceholderStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.SubText)).Background(theme.Color(ui.theme.InputBackground)))
+	form.AddFormItem(bodyInput)
 
-		form.AddFormItem(parent)
+	form.AddButton("Save", actionHandler(form)).
+		AddButton("Cancel", func() {
+			ui.hideForm(name)
+		})
+
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" %s Task ", action))
+
+	form.SetFieldStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.InputBackground)))
+	form.SetButtonStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)))
+	form.SetButtonActivatedStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
+
+	form.SetInputCapture(ui.formInputHandler)
+
+	return form
+}
+
+func (ui *UI) createMoveForm(name string, actionHandler formActionHandler) *form {
+	form := &form{
+		Form: tview.NewForm(),
+		name: name,
 	}
 
-	taskInput := tview.NewInputField().SetLabel("Task:").SetFieldWidth(80)
-	form.AddFormItem(taskInput)
+	parent := ui.createDropDown("Parent:", []string{})
+	form.AddFormItem(parent)
 
-	dropDown := tview.NewDropDown().SetLabel("Priority:").SetOptions([]string{"Critical", "High", "Medium", "Low"}, nil)
+	form.AddButton("Save", actionHandler(form)).
+		AddButton("Cancel", func() {
+			ui.hideForm(name)
+		})
 
-	dropDown.SetFocusedStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
-	dropDown.SetListStyles(
-		// unselected
+	form.SetBorder(true).SetTitle(" Move Task ")
+
+	form.SetFieldStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.InputBackground)))
+	form.SetButtonStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)))
+	form.SetButtonActivatedStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
+
+	form.SetInputCapture(ui.formInputHandler)
+
+	return form
+}
+
+func (ui *UI) createFilterForm(name string, actionHandler formActionHandler) *form {
+	form := &form{
+		Form: tview.NewForm(),
+		name: name,
+	}
+
+	tagInput := tview.NewInputField().SetLabel("Tag:").SetFieldWidth(40)
+	tagInput.SetAutocompleteFunc(func(current string) []string {
+		matches := []string{}
+
+		for _, tag := range ui.taskService.GetAllTags() {
+			if current != "" && strings.HasPrefix(tag, strings.ToLower(current)) {
+				matches = append(matches, tag)
+			}
+		}
+
+		return matches
+	})
+	tagInput.SetAutocompleteStyles(
+		theme.Color(ui.theme.Background),
 		tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)),
-		// selected
 		tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
-	dropDown.SetPrefixStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)))
+	form.AddFormItem(tagInput)
 
-	form.AddFormItem(dropDown)
-	form.AddButton("Save", actionHandler(form)).
+	form.AddButton("Filter", actionHandler(form)).
+		AddButton("Clear", func() {
+			ui.tagFilter = ""
+			ui.refresh()
+
+			ui.hideForm(name)
+		})
+
+	form.SetBorder(true).SetTitle(" Filter By Tag ")
+
+	form.SetFieldStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.InputBackground)))
+	form.SetButtonStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)))
+	form.SetButtonActivatedStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
+
+	form.SetInputCapture(ui.formInputHandler)
+
+	return form
+}
+
+func (ui *UI) createSessionForm(name string, actionHandler formActionHandler) *form {
+	form := &form{
+		Form: tview.NewForm(),
+		name: name,
+	}
+
+	form.AddInputField("Name:", "", 40, nil, nil)
+
+	form.AddButton("Start", actionHandler(form)).
 		AddButton("Cancel", func() {
 			ui.hideForm(name)
 		})
 
-	form.SetBorder(true).SetTitle(fmt.Sprintf(" %s Task ", action))
+	form.SetBorder(true).SetTitle(" Start Session ")
 
 	form.SetFieldStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.InputBackground)))
 	form.SetButtonStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)))
@@ -71,6 +166,20 @@ func (ui *UI) createForm(action string, name string, childForm bool, actionHandl
 	return form
 }
 
+func (ui *UI) createDropDown(label string, options []string) *tview.DropDown {
+	dropDown := tview.NewDropDown().SetLabel(label).SetOptions(options, nil)
+
+	dropDown.SetFocusedStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
+	dropDown.SetListStyles(
+		// unselected
+		tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)),
+		// selected
+		tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
+	dropDown.SetPrefixStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background)))
+
+	return dropDown
+}
+
 func (ui *UI) createNoteForm(name string, actionHandler formActionHandler) *form {
 	form := &form{
 		Form: tview.NewForm(),
@@ -109,7 +218,7 @@ func (ui *UI) formInputHandler(event *tcell.EventKey) *tcell.EventKey {
 func (ui *UI) showNewTaskForm(child bool, parentID int, parents []int) {
 	if !child {
 		ui.activeForm = ui.addTaskForm
-		ui.showTaskForm("", 0, addTaskFormName)
+		ui.showTaskForm(Task.Details{}, addTaskFormName)
 
 		return
 	}
@@ -131,20 +240,31 @@ func (ui *UI) showNewTaskForm(child bool, parentID int, parents []int) {
 	parentDrop.SetCurrentOption(selected)
 
 	ui.activeForm = ui.addChildTaskForm
-	ui.showTaskForm("", 0, addChildTaskFormName)
+	ui.showTaskForm(Task.Details{}, addChildTaskFormName)
 }
 
-func (ui *UI) showEditTaskForm(task string, priority int) {
+func (ui *UI) showEditTaskForm(details Task.Details) {
 	ui.activeForm = ui.editTaskForm
-	ui.showTaskForm(task, priority, editTaskFormName)
+	ui.showTaskForm(details, editTaskFormName)
 }
 
-func (ui *UI) showTaskForm(task string, priority int, form string) {
+func (ui *UI) showTaskForm(details Task.Details, form string) {
 	taskInput := ui.activeForm.GetFormItemByLabel("Task:").(*tview.InputField)
 	priorityDropDown := ui.activeForm.GetFormItemByLabel("Priority:").(*tview.DropDown)
+	dueInput := ui.activeForm.GetFormItemByLabel("Due:").(*tview.InputField)
+	tagsInput := ui.activeForm.GetFormItemByLabel("Tags:").(*tview.InputField)
+	bodyInput := ui.activeForm.GetFormItemByLabel("Notes:").(*tview.TextArea)
+
+	taskInput.SetText(details.Description)
+	priorityDropDown.SetCurrentOption(details.Priority)
+	tagsInput.SetText(strings.Join(details.Tags, ", "))
+	bodyInput.SetText(details.Body, false)
 
-	taskInput.SetText(task)
-	priorityDropDown.SetCurrentOption(priority)
+	if details.DueDate.IsZero() {
+		dueInput.SetText("")
+	} else {
+		dueInput.SetText(details.DueDate.Format(time.DateOnly))
+	}
 
 	ui.pages.ShowPage(form)
 	ui.app.SetFocus(taskInput)
@@ -165,9 +285,24 @@ func (ui *UI) hideForm(form string) {
 }
 
 func (ui *UI) showNoteForm() {
+	ui.noteForSession = false
+	ui.addNoteForm.SetTitle(" Notes ")
+
+	ui.openNoteForm(ui.taskService.GetNote())
+}
+
+// showSessionNoteForm opens the note form to edit the note of a past session
+func (ui *UI) showSessionNoteForm(id int) {
+	ui.noteForSession = true
+	ui.noteSessionID = id
+	ui.addNoteForm.SetTitle(fmt.Sprintf(" Notes For %s ", ui.taskService.GetSessionDate(id)))
+
+	ui.openNoteForm(ui.taskService.GetNoteForSession(id))
+}
+
+func (ui *UI) openNoteForm(note string) {
 	ui.activeForm = ui.addNoteForm
 
-	note := ui.taskService.GetNote()
 	input := ui.activeForm.GetFormItem(0).(*tview.TextArea)
 	input.SetText(note, true)
 	ui.app.SetFocus(ui.activeForm)
@@ -178,19 +313,66 @@ func (ui *UI) showNoteForm() {
 	ui.formOpen = true
 }
 
-func (ui *UI) addTaskActionHandler(form *form) func() {
-	return func() {
-		taskDescInput := form.GetFormItemByLabel("Task:").(*tview.InputField)
-		priorityDropDown := form.GetFormItemByLabel("Priority:").(*tview.DropDown)
+func (ui *UI) showMoveForm(taskID int) {
+	parentDrop := ui.moveTaskForm.GetFormItemByLabel("Parent:").(*tview.DropDown)
+
+	ui.moveParentIDs = ui.taskService.GetPossibleParents(taskID)
+	currentParent := ui.taskService.GetParent(taskID)
+
+	options := []string{"(top level)"}
+	selected := 0
+
+	for idx, parent := range ui.moveParentIDs {
+		if ui.taskService.HasParent(taskID) && parent == currentParent {
+			selected = idx + 1
+		}
 
-		taskDesc := taskDescInput.GetText()
-		priority, _ := priorityDropDown.GetCurrentOption()
+		options = append(options, ui.taskService.FormDisplayString(parent))
+	}
+
+	parentDrop.SetOptions(options, nil)
+	parentDrop.SetCurrentOption(selected)
 
-		if taskDesc == "" {
+	ui.activeForm = ui.moveTaskForm
+
+	ui.pages.ShowPage(moveTaskFormName)
+	ui.app.SetFocus(parentDrop)
+
+	ui.formOpen = true
+}
+
+func (ui *UI) showFilterForm() {
+	tagInput := ui.filterForm.GetFormItemByLabel("Tag:").(*tview.InputField)
+	tagInput.SetText(ui.tagFilter)
+
+	ui.activeForm = ui.filterForm
+
+	ui.pages.ShowPage(filterFormName)
+	ui.app.SetFocus(tagInput)
+
+	ui.formOpen = true
+}
+
+func (ui *UI) showSessionForm() {
+	nameInput := ui.sessionForm.GetFormItemByLabel("Name:").(*tview.InputField)
+	nameInput.SetText("")
+
+	ui.activeForm = ui.sessionForm
+
+	ui.pages.ShowPage(sessionFormName)
+	ui.app.SetFocus(nameInput)
+
+	ui.formOpen = true
+}
+
+func (ui *UI) addTaskActionHandler(form *form) func() {
+	return func() {
+		details, ok := ui.readTaskForm(form)
+		if !ok {
 			return
 		}
 
-		ui.taskService.AddTask(taskDesc, priority)
+		ui.taskService.AddTask(details)
 		ui.refresh()
 
 		ui.hideForm(addTaskFormName)
@@ -200,18 +382,14 @@ func (ui *UI) addTaskActionHandler(form *form) func() {
 func (ui *UI) addChildTaskActionHandler(form *form) func() {
 	return func() {
 		parentDropDown := form.GetFormItemByLabel("Parent:").(*tview.DropDown)
-		taskDescInput := form.GetFormItemByLabel("Task:").(*tview.InputField)
-		priorityDropDown := form.GetFormItemByLabel("Priority:").(*tview.DropDown)
-
-		taskDesc := taskDescInput.GetText()
-		priority, _ := priorityDropDown.GetCurrentOption()
 		_, parent := parentDropDown.GetCurrentOption()
 
-		if taskDesc == "" {
+		details, ok := ui.readTaskForm(form)
+		if !ok {
 			return
 		}
 
-		ui.taskService.AddChildTask(taskDesc, priority, parent)
+		ui.taskService.AddChildTask(details, parent)
 		ui.refresh()
 
 		ui.hideForm(addChildTaskFormName)
@@ -220,19 +398,14 @@ func (ui *UI) addChildTaskActionHandler(form *form) func() {
 
 func (ui *UI) editTaskActionHandler(form *form) func() {
 	return func() {
-		taskDescInput := form.GetFormItemByLabel("Task:").(*tview.InputField)
-		priorityDropDown := form.GetFormItemByLabel("Priority:").(*tview.DropDown)
-
-		taskDesc := taskDescInput.GetText()
-		priority, _ := priorityDropDown.GetCurrentOption()
-
-		if taskDesc == "" {
+		details, ok := ui.readTaskForm(form)
+		if !ok {
 			return
 		}
 
 		task := ui.todoList.GetCurrentNode().GetReference().(*task)
 
-		ui.taskService.EditTask(task.id, taskDesc, priority)
+		ui.taskService.EditTask(task.id, details)
 		ui.refresh()
 
 		ui.hideForm(editTaskFormName)
@@ -249,9 +422,101 @@ func (ui *UI) addNoteActionHandler(form *form) func() {
 			return
 		}
 
+		if ui.noteForSession {
+			ui.taskService.SaveNoteForSession(ui.noteSessionID, contents)
+			ui.hideForm(noteFormName)
+			ui.refreshSessionDetails()
+
+			return
+		}
+
 		ui.taskService.SaveNote(contents)
 		ui.refresh()
 
 		ui.hideForm(noteFormName)
 	}
 }
+
+func (ui *UI) moveTaskActionHandler(form *form) func() {
+	return func() {
+		parentDropDown := form.GetFormItemByLabel("Parent:").(*tview.DropDown)
+		selected, _ := parentDropDown.GetCurrentOption()
+
+		task := ui.todoList.GetCurrentNode().GetReference().(*task)
+
+		// the first option moves the task to the top level
+		if selected <= 0 {
+			ui.taskService.RemoveChild(task.id)
+		} else {
+			ui.taskService.AddChild(ui.moveParentIDs[selected-1], task.id)
+		}
+
+		ui.refresh()
+
+		ui.hideForm(moveTaskFormName)
+	}
+}
+
+// readTaskForm reads the details from a task form, the form is invalid if it
+// doesn't have a description or the due date can't be parsed. an empty due
+// date is valid and means the task isn't due. due dates are in the session
+// timezone.
+func (ui *UI) readTaskForm(form *form) (Task.Details, bool) {
+	taskDescInput := form.GetFormItemByLabel("Task:").(*tview.InputField)
+	priorityDropDown := form.GetFormItemByLabel("Priority:").(*tview.DropDown)
+	dueInput := form.GetFormItemByLabel("Due:").(*tview.InputField)
+	tagsInput := form.GetFormItemByLabel("Tags:").(*tview.InputField)
+	bodyInput := form.GetFormItemByLabel("Notes:").(*tview.TextArea)
+
+	details := Task.Details{
+		Description: taskDescInput.GetText(),
+		Tags:        Task.ParseTags(tagsInput.GetText()),
+		Body:        bodyInput.GetText(),
+	}
+	details.Priority, _ = priorityDropDown.GetCurrentOption()
+
+	if details.Description == "" {
+		return details, false
+	}
+
+	if dueInput.GetText() != "" {
+		dueDate, err := ui.taskService.ParseDate(dueInput.GetText())
+		if err != nil {
+			return details, false
+		}
+
+		details.DueDate = dueDate
+	}
+
+	return details, true
+}
+
+func (ui *UI) filterActionHandler(form *form) func() {
+	return func() {
+		tagInput := form.GetFormItemByLabel("Tag:").(*tview.InputField)
+
+		// an empty filter shows every task again
+		ui.tagFilter = ""
+		if tags := Task.ParseTags(tagInput.GetText()); len(tags) > 0 {
+			ui.tagFilter = tags[0]
+		}
+
+		ui.refresh()
+
+		ui.hideForm(filterFormName)
+	}
+}
+
+func (ui *UI) startSessionActionHandler(form *form) func() {
+	return func() {
+		nameInput := form.GetFormItemByLabel("Name:").(*tview.InputField)
+
+		ui.taskService.StartSession(nameInput.GetText())
+
+		ui.hideForm(sessionFormName)
+
+		// the new session is the most recent, at the top of the list
+		ui.sessionList.SetCurrentItem(0)
+		ui.refreshSessionDetails()
+	}
+}
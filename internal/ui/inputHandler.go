@@ -12,13 +12,17 @@ func (ui *UI) listInputHandler() func(event *tcell.EventKey) *tcell.EventKey {
 			ui.focusCurrentNode()
 		}
 
-		// let the child lists active inputHandler be called first
-		if ui.activeTaskList.handleInput(event) == nil {
+		// let the focused list's inputHandler be called first, the task
+		// handlers need a selected task so they're skipped for the session list
+		if ui.sessionListFocused {
+			event = ui.sessionList.handleInput(event)
+			if event == nil {
+				return nil
+			}
+		} else if ui.activeTaskList.handleInput(event) == nil {
 			return nil
 		}
 
-		// TODO: ui.sessionList.handleInput(event)
-
 		switch event.Key() {
 		case tcell.KeyCtrlJ: // down
 			if ui.formOpen || ui.sessionListFocused {
@@ -51,6 +55,7 @@ func (ui *UI) listInputHandler() func(event *tcell.EventKey) *tcell.EventKey {
 			ui.sessionListFocused = true
 			ui.app.SetFocus(ui.sessionList)
 			ui.focus(nil)
+			ui.showSessionDetails(ui.sessionList.GetCurrentItem())
 			return nil
 
 		case tcell.KeyCtrlH: // left
@@ -61,6 +66,7 @@ func (ui *UI) listInputHandler() func(event *tcell.EventKey) *tcell.EventKey {
 			ui.sessionListFocused = false
 
 			ui.focus(ui.activeTaskList)
+			ui.showTaskDetails(ui.activeTaskList.GetCurrentNode())
 			return nil
 
 		case tcell.KeyEnter:
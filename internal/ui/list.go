@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -12,10 +15,12 @@ type list struct {
 }
 
 func (ui *UI) refresh() {
+	ui.stopDeletedPomodoro()
 	ui.refreshSessionList(ui.sessionList)
 	ui.refreshTrees()
 
 	ui.focus(ui.activeTaskList)
+	ui.showTaskDetails(ui.activeTaskList.GetCurrentNode())
 }
 
 func (ui *UI) refreshSessionList(list *list) {
@@ -23,14 +28,26 @@ func (ui *UI) refreshSessionList(list *list) {
 	list.Clear()
 
 	sessionIDs := ui.taskService.GetAllSessionIDs(true)
+	ui.sessionIDs = sessionIDs
 
 	if len(sessionIDs) == 0 {
 		list.AddItem("No Sessions!", "", 0, nil)
 		return
 	}
 
+	matches := []int{}
+	if ui.searchQuery != "" {
+		matches = ui.taskService.SearchSessions(ui.searchQuery)
+	}
+
 	for _, id := range sessionIDs {
 		listItemText := ui.taskService.SessionDisplayString(id)
+
+		// sessions with notes that match the search are underlined
+		if slices.Contains(matches, id) {
+			listItemText = fmt.Sprintf("[::u]%s[::U]", listItemText)
+		}
+
 		list.AddItem(listItemText, "", 0, nil)
 	}
 
@@ -46,6 +63,19 @@ func (ui *UI) refreshSessionList(list *list) {
 }
 
 func (ui *UI) genericTreeInputHandler(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyCtrlR {
+		if ui.taskService.Redo() {
+			ui.refresh()
+		}
+
+		return nil
+	}
+
+	if event.Key() == tcell.KeyEsc && ui.searchQuery != "" {
+		ui.clearSearch()
+		return nil
+	}
+
 	switch event.Rune() {
 	case ' ':
 		selectedNode := ui.activeTaskList.GetCurrentNode()
@@ -77,19 +107,131 @@ func (ui *UI) genericTreeInputHandler(event *tcell.EventKey) *tcell.EventKey {
 
 		ui.refresh()
 
+		return nil
+	case 'X':
+		ui.showTrash()
+		return nil
+	case 'f':
+		ui.showFilterForm()
 		return nil
 	case 'a':
 		ui.showNewTaskForm(false, 0, nil)
 		return nil
 
+	case 'u':
+		if ui.taskService.Undo() {
+			ui.refresh()
+		}
+
+		return nil
+
 	case 'n':
+		// while searching n jumps to the next match instead
+		if ui.searchQuery != "" {
+			ui.jumpToMatch(1)
+			return nil
+		}
+
 		ui.showNoteForm()
 		return nil
+	case 'N':
+		ui.jumpToMatch(-1)
+		return nil
+	case '/':
+		ui.showSearch()
+		return nil
+
+	case 'q':
+		ui.app.Stop()
+		return nil
+	}
+
+	return event
+}
+
+// sessionInputHandler handles the session list's keys, the task keys that
+// don't need a selected task are handled here as well
+func (ui *UI) sessionInputHandler(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyCtrlR {
+		if ui.taskService.Redo() {
+			ui.refreshSessionDetails()
+		}
+
+		return nil
+	}
+
+	id, ok := ui.selectedSession()
+
+	switch event.Rune() {
+	case 'n':
+		if ok {
+			ui.showSessionNoteForm(id)
+		}
+
+		return nil
+	case 's':
+		ui.showSessionForm()
+
+		return nil
+	case 'S':
+		if ui.taskService.StopSession() {
+			ui.refreshSessionDetails()
+		}
+
+		return nil
+	case 'r':
+		if ok && ui.taskService.ReplanSession(id) > 0 {
+			ui.refreshSessionDetails()
+		}
+
+		return nil
+	case 'u':
+		if ui.taskService.Undo() {
+			ui.refreshSessionDetails()
+		}
 
+		return nil
 	case 'q':
 		ui.app.Stop()
 		return nil
+	case 'j':
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case 'k':
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
 	}
 
 	return event
 }
+
+func (ui *UI) selectedSession() (int, bool) {
+	index := ui.sessionList.GetCurrentItem()
+
+	if index < 0 || index >= len(ui.sessionIDs) {
+		return 0, false
+	}
+
+	return ui.sessionIDs[index], true
+}
+
+// showSessionDetails shows the session at index of the session list in the
+// detail pane
+func (ui *UI) showSessionDetails(index int) {
+	ui.detailPane.SetTitle(" Session ")
+
+	if index < 0 || index >= len(ui.sessionIDs) {
+		ui.detailPane.SetText("")
+		return
+	}
+
+	ui.detailPane.SetText(ui.parseColors(ui.taskService.SessionDetailString(ui.sessionIDs[index]))).ScrollToBeginning()
+}
+
+// refreshSessionDetails refreshes everything while keeping the session list
+// focused
+func (ui *UI) refreshSessionDetails() {
+	ui.refresh()
+
+	ui.app.SetFocus(ui.sessionList)
+	ui.focus(nil)
+	ui.showSessionDetails(ui.sessionList.GetCurrentItem())
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	Task "github.com/darwinfroese/scribe/internal/task"
+)
+
+const (
+	pomodoroWork  = "focus"
+	pomodoroBreak = "break"
+)
+
+// pomodoro is a running focus timer, a work phase on a task followed by a
+// break
+type pomodoro struct {
+	taskID int
+	phase  string
+	ends   time.Time
+}
+
+// SetPomodoroDurations sets how long the work and break phases of a pomodoro
+// last
+func (ui *UI) SetPomodoroDurations(work, rest time.Duration) {
+	ui.workDuration = work
+	ui.breakDuration = rest
+}
+
+// togglePomodoro starts a pomodoro for the task, or stops the running one
+func (ui *UI) togglePomodoro(taskID int) {
+	if ui.pomodoro != nil {
+		ui.pomodoro = nil
+		ui.setStatus("pomodoro stopped")
+
+		return
+	}
+
+	ui.pomodoro = &pomodoro{
+		taskID: taskID,
+		phase:  pomodoroWork,
+		ends:   time.Now().Add(ui.workDuration),
+	}
+
+	ui.refreshPomodoro()
+}
+
+// refreshPomodoro updates the countdown in the status bar, moving on to the
+// next phase once the current one ends
+func (ui *UI) refreshPomodoro() {
+	if ui.pomodoro == nil || ui.stopDeletedPomodoro() {
+		return
+	}
+
+	remaining := time.Until(ui.pomodoro.ends)
+
+	if remaining <= 0 {
+		ui.endPomodoroPhase()
+		return
+	}
+
+	details, _ := ui.taskService.GetTaskDetails(ui.pomodoro.taskID)
+	seconds := int(remaining.Round(time.Second).Seconds())
+
+	ui.setStatus(fmt.Sprintf("[::b]%s[::B] %02d:%02d [%s::]%s[white::] (P: stop)",
+		ui.pomodoro.phase, seconds/60, seconds%60, Task.SubTextColorKey, details.Description))
+}
+
+// stopDeletedPomodoro stops the pomodoro if its task no longer exists, e.g.
+// it was deleted or adding it was undone, and returns whether it did
+func (ui *UI) stopDeletedPomodoro() bool {
+	if ui.pomodoro == nil {
+		return false
+	}
+
+	if _, ok := ui.taskService.GetTaskDetails(ui.pomodoro.taskID); ok {
+		return false
+	}
+
+	ui.pomodoro = nil
+	ui.setStatus("pomodoro stopped, its task was deleted")
+
+	return true
+}
+
+// endPomodoroPhase logs a finished focus block and starts the break, or
+// stops the pomodoro after the break, ringing the bell either way
+func (ui *UI) endPomodoroPhase() {
+	if ui.screen != nil {
+		_ = ui.screen.Beep()
+	}
+
+	if ui.pomodoro.phase == pomodoroBreak {
+		ui.pomodoro = nil
+		ui.setStatus("break over, press P to start another focus block")
+
+		return
+	}
+
+	ui.taskService.LogPomodoro(ui.pomodoro.taskID, ui.workDuration)
+
+	ui.pomodoro.phase = pomodoroBreak
+	ui.pomodoro.ends = time.Now().Add(ui.breakDuration)
+
+	if !ui.formOpen {
+		ui.showTaskDetails(ui.activeTaskList.GetCurrentNode())
+	}
+
+	ui.refreshPomodoro()
+}
+
+func (ui *UI) setStatus(text string) {
+	ui.statusBar.SetText(ui.parseColors(" " + text))
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/darwinfroese/scribe/internal/theme"
+)
+
+func (ui *UI) createSearchInput() *tview.InputField {
+	input := tview.NewInputField().
+		SetLabel("/").
+		SetFieldStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.InputBackground))).
+		SetChangedFunc(ui.search)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			// keep the matches highlighted so they can be jumped between with n/N
+			ui.hideForm(searchPageName)
+		case tcell.KeyEsc:
+			ui.hideForm(searchPageName)
+			ui.clearSearch()
+		}
+	})
+
+	input.SetBorder(true).SetTitle(" Search (enter: keep matches, esc: clear) ")
+
+	return input
+}
+
+func (ui *UI) showSearch() {
+	ui.sessionListFocused = false
+
+	ui.searchInput.SetText(ui.searchQuery)
+
+	ui.pages.ShowPage(searchPageName)
+	ui.app.SetFocus(ui.searchInput)
+
+	ui.formOpen = true
+}
+
+// search highlights the tasks and sessions that match query and selects the
+// first matching task
+func (ui *UI) search(query string) {
+	ui.searchQuery = query
+	ui.refresh()
+
+	ui.jumpToMatch(0)
+
+	if ui.formOpen {
+		ui.app.SetFocus(ui.searchInput)
+	}
+}
+
+func (ui *UI) clearSearch() {
+	ui.searchQuery = ""
+	ui.refresh()
+}
+
+// jumpToMatch selects the next (a positive offset) or previous (a negative
+// offset) matching task in the active tree, wrapping around at either end.
+// an offset of 0 selects the first match, switching to the other tree if
+// only it has matches.
+func (ui *UI) jumpToMatch(offset int) {
+	if ui.searchQuery == "" {
+		return
+	}
+
+	if offset == 0 && len(ui.searchMatches(ui.activeTaskList)) == 0 {
+		other := ui.todoList
+		if ui.activeTaskList == ui.todoList {
+			other = ui.completedList
+		}
+
+		if len(ui.searchMatches(other)) > 0 {
+			ui.activeTaskList = other
+			ui.focus(other)
+		}
+	}
+
+	tree := ui.activeTaskList
+	matches := ui.searchMatches(tree)
+
+	if len(matches) == 0 {
+		return
+	}
+
+	nodes := taskNodes(tree)
+	current := slices.Index(nodes, tree.GetCurrentNode())
+	target := matches[0]
+
+	switch {
+	case offset > 0:
+		for _, match := range matches {
+			if slices.Index(nodes, match) > current {
+				target = match
+				break
+			}
+		}
+	case offset < 0:
+		target = matches[len(matches)-1]
+
+		for _, match := range slices.Backward(matches) {
+			if slices.Index(nodes, match) < current {
+				target = match
+				break
+			}
+		}
+	}
+
+	tree.focusedNode = target
+	tree.SetCurrentNode(target)
+}
+
+// searchMatches returns the nodes in tree for tasks that match the search,
+// in the order they're shown
+func (ui *UI) searchMatches(tree *tree) []*tview.TreeNode {
+	ids := ui.taskService.SearchTasks(ui.searchQuery)
+	matches := []*tview.TreeNode{}
+
+	for _, node := range taskNodes(tree) {
+		if slices.Contains(ids, node.GetReference().(*task).id) {
+			matches = append(matches, node)
+		}
+	}
+
+	return matches
+}
+
+// taskNodes returns every node in tree that is a task, in the order they're
+// shown
+func taskNodes(tree *tree) []*tview.TreeNode {
+	nodes := []*tview.TreeNode{}
+
+	tree.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+		if _, ok := node.GetReference().(*task); ok {
+			nodes = append(nodes, node)
+		}
+
+		return true
+	})
+
+	return nodes
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/darwinfroese/scribe/internal/theme"
+)
+
+func (ui *UI) createTrashList() *list {
+	trash := &list{
+		List: tview.NewList().
+			ShowSecondaryText(false).
+			SetHighlightFullLine(true).
+			SetSelectedStyle(
+				tcell.StyleDefault.
+					Foreground(theme.Color(ui.theme.TextFocus)).
+					Background(theme.Color(ui.theme.BackgroundFocus))),
+	}
+
+	trash.handleInput = ui.trashInputHandler
+	trash.SetInputCapture(trash.handleInput)
+	trash.SetBorder(true).SetTitle(" Trash (r: restore, x: delete forever, esc: close) ")
+
+	return trash
+}
+
+func (ui *UI) showTrash() {
+	ui.refreshTrash()
+
+	ui.pages.ShowPage(trashPageName)
+	ui.app.SetFocus(ui.trashList)
+
+	ui.formOpen = true
+}
+
+func (ui *UI) refreshTrash() {
+	originalIndex := ui.trashList.GetCurrentItem()
+	ui.trashList.Clear()
+
+	ui.trashIDs = ui.taskService.GetDeletedTaskIDs()
+
+	if len(ui.trashIDs) == 0 {
+		ui.trashList.AddItem("The trash is empty!", "", 0, nil)
+		return
+	}
+
+	for _, id := range ui.trashIDs {
+		ui.trashList.AddItem(ui.taskService.TrashString(id), "", 0, nil)
+	}
+
+	ui.trashList.SetCurrentItem(min(max(originalIndex, 0), len(ui.trashIDs)-1))
+}
+
+func (ui *UI) trashInputHandler(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEsc {
+		ui.hideForm(trashPageName)
+		return nil
+	}
+
+	if len(ui.trashIDs) == 0 {
+		return event
+	}
+
+	selected := ui.trashIDs[ui.trashList.GetCurrentItem()]
+
+	switch event.Rune() {
+	case 'r':
+		ui.taskService.RestoreTask(selected)
+	case 'x':
+		ui.taskService.PurgeTask(selected)
+	case 'j':
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case 'k':
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	default:
+		return event
+	}
+
+	ui.refreshTrash()
+	ui.refresh()
+
+	// refreshing the trees moves the focus back to them
+	ui.app.SetFocus(ui.trashList)
+
+	return nil
+}
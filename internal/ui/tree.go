@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"fmt"
 	"slices"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -10,10 +12,6 @@ import (
 	"github.com/darwinfroese/scribe/internal/theme"
 )
 
-const (
-	parentTreeLevel = 1
-)
-
 type tree struct {
 	*tview.TreeView
 
@@ -22,83 +20,31 @@ type tree struct {
 }
 
 func (ui *UI) selectNextClosest(tree *tree, node *tview.TreeNode) {
-	if node.GetLevel() == parentTreeLevel {
-		ui.selectNextClosestParent(tree, node)
-		return
-	}
-
-	ui.selectNextClosestChild(tree, node)
-}
-
-func (ui *UI) selectNextClosestParent(tree *tree, node *tview.TreeNode) {
-	parents := tree.GetRoot().GetChildren()
-
-	// we should end up with a "No Tasks!" selection when this returns
-	if len(parents) == 1 {
-		return
-	}
-
-	if parents[0] == node {
-		tree.focusedNode = parents[1]
-		tree.SetCurrentNode(parents[1])
+	parent := ui.findParentNode(tree.GetRoot(), node)
+	if parent == nil {
 		return
 	}
 
-	if parents[len(parents)-1] == node {
-		tree.focusedNode = parents[len(parents)-2]
-		tree.SetCurrentNode(parents[len(parents)-2])
-		return
-	}
+	siblings := parent.GetChildren()
 
-	for idx, parent := range parents {
-		if parent == node {
-			tree.focusedNode = parents[idx-1]
-			tree.SetCurrentNode(parents[idx-1])
+	if len(siblings) == 1 {
+		// we should end up with a "No Tasks!" selection when this returns
+		if parent == tree.GetRoot() {
 			return
 		}
-	}
-}
-
-func (ui *UI) selectNextClosestChild(tree *tree, node *tview.TreeNode) {
-	parents := tree.GetRoot().GetChildren()
-	var children []*tview.TreeNode
-	var parent *tview.TreeNode
-
-	for _, prnt := range parents {
-		pChildren := prnt.GetChildren()
-
-		if slices.Contains(pChildren, node) {
-			children = pChildren
-			parent = prnt
-			break
-		}
-	}
 
-	if len(children) == 1 {
 		tree.focusedNode = parent
 		tree.SetCurrentNode(parent)
 		return
 	}
 
-	if children[0] == node {
-		tree.focusedNode = children[1]
-		tree.SetCurrentNode(children[1])
-		return
+	next := slices.Index(siblings, node) - 1
+	if next < 0 {
+		next = 1
 	}
 
-	if children[len(children)-1] == node {
-		tree.focusedNode = children[len(children)-2]
-		tree.SetCurrentNode(children[len(children)-2])
-		return
-	}
-
-	for idx, child := range children {
-		if child == node {
-			tree.focusedNode = children[idx-1]
-			tree.SetCurrentNode(children[idx-1])
-			return
-		}
-	}
+	tree.focusedNode = siblings[next]
+	tree.SetCurrentNode(siblings[next])
 }
 
 func (ui *UI) setCurrentNode(t *tree, node *tview.TreeNode) {
@@ -108,19 +54,7 @@ func (ui *UI) setCurrentNode(t *tree, node *tview.TreeNode) {
 	}
 
 	task := node.GetReference().(*task)
-	root := t.GetRoot()
-
-	if ui.taskService.HasParent(task.id) {
-		parentID := ui.taskService.GetParent(task.id)
-		root = ui.findNode(root, parentID)
-	}
-
-	if root == nil {
-		t.SetCurrentNode(t.GetRoot().GetChildren()[0])
-		return
-	}
-
-	target := ui.findNode(root, task.id)
+	target := ui.findTaskNode(t.GetRoot(), task.id)
 
 	if target != nil {
 		t.SetCurrentNode(target)
@@ -129,26 +63,43 @@ func (ui *UI) setCurrentNode(t *tree, node *tview.TreeNode) {
 	}
 }
 
-func (ui *UI) findNode(root *tview.TreeNode, id int) *tview.TreeNode {
-	children := root.GetChildren()
-	idx := slices.IndexFunc(children, func(node *tview.TreeNode) bool {
-		nTask := node.GetReference().(*task)
+// findParentNode searches every level of the tree under root for the parent of node
+func (ui *UI) findParentNode(root, node *tview.TreeNode) *tview.TreeNode {
+	var found *tview.TreeNode
 
-		return nTask.id == id
-	})
+	root.Walk(func(current, parent *tview.TreeNode) bool {
+		if found != nil {
+			return false
+		}
 
-	if idx == -1 {
-		return nil
-	}
+		if current == node {
+			found = parent
+			return false
+		}
+
+		return true
+	})
 
-	return children[idx]
+	return found
 }
 
 func (ui *UI) refreshTrees() {
+	ui.todoList.SetTitle(ui.treeTitle("Todo Tasks"))
+	ui.completedList.SetTitle(ui.treeTitle("Completed Tasks"))
+
 	ui.refreshTaskTree(ui.todoList, hideCompleted)
 	ui.refreshTaskTree(ui.completedList, hideIncomplete)
 }
 
+// treeTitle includes the tag the trees are filtered by in the title
+func (ui *UI) treeTitle(title string) string {
+	if ui.tagFilter == "" {
+		return fmt.Sprintf(" %s ", title)
+	}
+
+	return fmt.Sprintf(" %s (#%s) ", title, ui.tagFilter)
+}
+
 func (ui *UI) refreshTaskTree(tree *tree, filter bool) {
 	var ids []int
 	var sortOrder int
@@ -180,16 +131,75 @@ func (ui *UI) refreshTaskTree(tree *tree, filter bool) {
 		return
 	}
 
-	if tree.focusedNode == nil {
+	// the focused task may have been removed from this tree, e.g. by an undo
+	if !ui.hasFocusedTask(tree) {
 		tree.focusedNode = tree.GetRoot().GetChildren()[0]
 	}
 }
 
+// showTaskDetails shows the task of node in the detail pane
+func (ui *UI) showTaskDetails(node *tview.TreeNode) {
+	if node == nil {
+		return
+	}
+
+	ui.detailPane.SetTitle(" Details ")
+
+	selected, ok := node.GetReference().(*task)
+	if !ok {
+		ui.detailPane.SetText("")
+		return
+	}
+
+	ui.detailPane.SetText(ui.parseColors(ui.taskService.DetailString(selected.id))).ScrollToBeginning()
+}
+
+func (ui *UI) hasFocusedTask(tree *tree) bool {
+	if tree.focusedNode == nil {
+		return false
+	}
+
+	focused, ok := tree.focusedNode.GetReference().(*task)
+	if !ok {
+		return false
+	}
+
+	return ui.findTaskNode(tree.GetRoot(), focused.id) != nil
+}
+
+// findTaskNode searches every level of the tree under root for the node of the task with id
+func (ui *UI) findTaskNode(root *tview.TreeNode, id int) *tview.TreeNode {
+	var found *tview.TreeNode
+
+	root.Walk(func(node, parent *tview.TreeNode) bool {
+		if found != nil {
+			return false
+		}
+
+		if nTask, ok := node.GetReference().(*task); ok && nTask.id == id {
+			found = node
+			return false
+		}
+
+		return true
+	})
+
+	return found
+}
+
 func (ui *UI) addNode(base *tview.TreeNode, id, sortOrder int) {
+	if ui.tagFilter != "" && !ui.taskService.MatchesTag(id, ui.tagFilter) {
+		return
+	}
+
 	text := ui.parseColors(ui.taskService.DisplayString(id))
 	task := &task{id, text}
 
-	listItemText := ui.parseColors(ui.taskService.DisplayString(id))
+	listItemText := text
+	if ui.searchQuery != "" {
+		listItemText = ui.parseColors(ui.taskService.SearchDisplayString(id, ui.searchQuery))
+	}
+
 	node := tview.NewTreeNode(listItemText).
 		SetSelectedTextStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus))).
 		SetReference(task)
@@ -217,73 +227,63 @@ func (ui *UI) wipInputHandler(event *tcell.EventKey) *tcell.EventKey {
 			return event
 		}
 
+		// any incomplete task can be a parent, defaulting to the selected one
 		task := selected.(*task)
-		parent := ui.taskService.GetParent(task.id)
-		parents := ui.taskService.GetAllParents()
+		parents := ui.taskService.GetIncompleteTaskIDs(Task.SortOrderNone)
 
-		ui.showNewTaskForm(true, parent, parents)
+		ui.showNewTaskForm(true, task.id, parents)
 		return nil
 	case 'K': // UP
-		children := ui.activeTaskList.GetRoot().GetChildren()
-		selected := ui.activeTaskList.GetCurrentNode()
-
-		for idx, child := range children {
-			if child == selected {
-				if idx == 0 {
-					return nil
-				}
-
-				children[idx] = children[idx-1]
-				children[idx-1] = selected
-				ui.activeTaskList.GetRoot().SetChildren(children)
-
-				return nil
-			}
+		selected := ui.activeTaskList.GetCurrentNode().GetReference()
+		if selected == nil {
+			return event
 		}
 
+		ui.moveTask(selected.(*task).id, -1)
+
 		return nil
 	case 'J':
-		children := ui.activeTaskList.GetRoot().GetChildren()
-		selected := ui.activeTaskList.GetCurrentNode()
-
-		for idx, child := range children {
-			if child == selected {
-				if idx == len(children)-1 {
-					return nil
-				}
-
-				children[idx] = children[idx+1]
-				children[idx+1] = selected
-				ui.activeTaskList.GetRoot().SetChildren(children)
-
-				return nil
-			}
+		selected := ui.activeTaskList.GetCurrentNode().GetReference()
+		if selected == nil {
+			return event
 		}
 
+		ui.moveTask(selected.(*task).id, 1)
+
 		return nil
 	case 'S':
-		ui.todoListSortOrder = Task.SortOrderPriorityDesc
-		ui.refresh()
+		ui.setTodoSortOrder(Task.SortOrderPriorityDesc)
 
 		return nil
 	case 's':
-		ui.todoListSortOrder = Task.SortOrderPriorityAsc
-		ui.refresh()
+		ui.setTodoSortOrder(Task.SortOrderPriorityAsc)
+
+		return nil
+	case 'M':
+		ui.setTodoSortOrder(Task.SortOrderManual)
+
+		return nil
+	case 'd':
+		ui.setTodoSortOrder(Task.SortOrderDueDate)
 
 		return nil
 	case 'e':
-		selected := ui.todoList.GetCurrentNode().GetReference()
+		selected := ui.activeTaskList.GetCurrentNode().GetReference()
 		if selected == nil {
 			return nil
 		}
 
 		task := selected.(*task)
-		text, priority := ui.taskService.GetTaskDetails(task.id)
-		ui.showEditTaskForm(text, priority)
+		details, ok := ui.taskService.GetTaskDetails(task.id)
+		if !ok {
+			return nil
+		}
+
+		ui.showEditTaskForm(details)
 
 		return nil
 	case 'p':
-		selected := ui.todoList.GetCurrentNode().GetReference()
+		selected := ui.activeTaskList.GetCurrentNode().GetReference()
 		if selected == nil {
 			return event
 		}
@@ -299,38 +299,123 @@ func (ui *UI) wipInputHandler(event *tcell.EventKey) *tcell.EventKey {
 		ui.refresh()
 
 		return nil
-	case 't':
-		children := ui.todoList.GetRoot().GetChildren()
-		selected := ui.todoList.GetCurrentNode().GetReference()
+	case 'c':
+		selected := ui.activeTaskList.GetCurrentNode().GetReference()
+		if selected == nil {
+			return event
+		}
+
+		ui.taskService.ToggleTimer(selected.(*task).id)
+		ui.refresh()
 
+		return nil
+	case 'P':
+		selected := ui.activeTaskList.GetCurrentNode().GetReference()
 		if selected == nil {
 			return event
 		}
 
-		selectedTask := selected.(*task)
+		ui.togglePomodoro(selected.(*task).id)
 
-		for idx, child := range children {
-			childTask := child.GetReference().(*task)
-			if childTask.id == selectedTask.id {
-				if idx == 0 {
-					return nil
-				}
+		return nil
+	case 'T':
+		selected := ui.activeTaskList.GetCurrentNode().GetReference()
+		if selected == nil {
+			return event
+		}
 
-				parent := children[idx-1].GetReference().(*task)
+		ui.taskService.OutdentTask(selected.(*task).id)
+		ui.refresh()
 
-				ui.taskService.AddChild(parent.id, selectedTask.id)
-				ui.refresh()
+		return nil
+	case 'm':
+		selected := ui.activeTaskList.GetCurrentNode().GetReference()
+		if selected == nil {
+			return event
+		}
 
-				return nil
-			}
+		ui.showMoveForm(selected.(*task).id)
+
+		return nil
+	case 't':
+		selectedNode := ui.todoList.GetCurrentNode()
+		selected := selectedNode.GetReference()
+
+		if selected == nil {
+			return event
 		}
 
+		// the task is nested under the task above it at the same level
+		siblings := ui.findParentNode(ui.todoList.GetRoot(), selectedNode).GetChildren()
+		idx := slices.Index(siblings, selectedNode)
+
+		if idx <= 0 {
+			return nil
+		}
+
+		parent := siblings[idx-1].GetReference().(*task)
+
+		ui.taskService.AddChild(parent.id, selected.(*task).id)
+		ui.refresh()
+
 		return nil
 	}
 
 	return event
 }
 
+// moveTask moves a task among its siblings, switching the todo list to the
+// manual sort order so that the move is visible
+func (ui *UI) moveTask(id, offset int) {
+	ui.taskService.MoveTask(id, offset)
+
+	if ui.todoListSortOrder != Task.SortOrderManual {
+		ui.setTodoSortOrder(Task.SortOrderManual)
+		return
+	}
+
+	ui.refresh()
+}
+
+func (ui *UI) setTodoSortOrder(sortOrder int) {
+	ui.todoListSortOrder = sortOrder
+	ui.taskService.SetTodoSortOrder(sortOrder)
+
+	ui.refresh()
+}
+
 func (ui *UI) completeInputHandler(event *tcell.EventKey) *tcell.EventKey {
 	return ui.genericTreeInputHandler(event)
 }
+
+// tickTimers keeps the elapsed time of a running timer and the pomodoro
+// countdown up to date
+func (ui *UI) tickTimers() {
+	for range time.Tick(time.Second) {
+		ui.app.QueueUpdateDraw(func() {
+			ui.refreshTimers()
+			ui.refreshPomodoro()
+		})
+	}
+}
+
+// refreshTimers updates the text of every task in place, so the selection
+// and focus are left alone, while a timer is running
+func (ui *UI) refreshTimers() {
+	if _, ok := ui.taskService.GetTimedTaskID(); !ok {
+		return
+	}
+
+	for _, tree := range []*tree{ui.todoList, ui.completedList} {
+		for _, node := range taskNodes(tree) {
+			id := node.GetReference().(*task).id
+
+			text := ui.parseColors(ui.taskService.DisplayString(id))
+			if ui.searchQuery != "" {
+				text = ui.parseColors(ui.taskService.SearchDisplayString(id, ui.searchQuery))
+			}
+
+			node.SetText(text)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -18,8 +19,19 @@ const (
 	addTaskFormName      = "add-form"
 	addChildTaskFormName = "add-child-form"
 	editTaskFormName     = "edit-form"
+	moveTaskFormName     = "move-form"
+	filterFormName       = "filter-form"
+	sessionFormName      = "session-form"
 
 	noteFormName = "notes"
+
+	// the number of lines shown for a task's notes in the task forms
+	taskBodyHeight = 4
+
+	trashPageName      = "trash"
+	searchPageName     = "search"
+	carryOverPageName  = "carry-over"
+	writeErrorPageName = "write-error"
 )
 
 type UI struct {
@@ -28,12 +40,20 @@ type UI struct {
 	todoList      *tree
 	completedList *tree
 	sessionList   *list
+	trashList     *list
+	detailPane    *tview.TextView
+	statusBar     *tview.TextView
 
 	addTaskForm      *form
 	addChildTaskForm *form
 	editTaskForm     *form
+	moveTaskForm     *form
+	filterForm       *form
+	sessionForm      *form
 	addNoteForm      *form
 
+	searchInput *tview.InputField
+
 	pages *tview.Pages
 
 	taskService TaskService
@@ -42,12 +62,27 @@ type UI struct {
 	sessionListFocused bool
 
 	todoListSortOrder int
+	tagFilter         string
+	searchQuery       string
 
 	activeTaskList *tree
 	activeForm     *form
 
 	sessionIDs []int
 
+	// the past session whose note is being edited, the note form edits
+	// today's note when noteForSession is false
+	noteSessionID  int
+	noteForSession bool
+	trashIDs       []int
+
+	moveParentIDs []int
+
+	pomodoro      *pomodoro
+	workDuration  time.Duration
+	breakDuration time.Duration
+	screen        tcell.Screen
+
 	theme *theme.Theme
 }
 
@@ -57,14 +92,15 @@ type task struct {
 }
 
 type TaskService interface {
-	AddTask(description string, priority int)
-	AddChildTask(description string, priority int, parentDisplay string)
+	AddTask(details Task.Details)
+	AddChildTask(details Task.Details, parentDisplay string)
 	Count() int
 
 	GetAllTaskIDs() []int
 	GetCompletedTaskIDs(sortOrder int) []int
 	GetIncompleteTaskIDs(sortOrder int) []int
-	GetTaskDetails(id int) (string, int)
+	GetTaskDetails(id int) (Task.Details, bool)
+	DetailString(id int) string
 
 	GetParent(id int) int
 	GetAllParents() []int
@@ -73,10 +109,28 @@ type TaskService interface {
 	ToggleComplete(id int)
 	AddChild(parentID int, childID int)
 	RemoveChild(childID int)
+	OutdentTask(id int)
+	MoveTask(id, offset int)
+	GetPossibleParents(id int) []int
 	DeleteTask(id int)
-	EditTask(id int, description string, priority int)
+	EditTask(id int, details Task.Details)
 
 	TogglePlanTask(id int)
+	ToggleTimer(id int)
+	LogPomodoro(id int, length time.Duration)
+	GetTimedTaskID() (int, bool)
+
+	SearchTasks(query string) []int
+	SearchSessions(query string) []int
+	SearchDisplayString(id int, query string) string
+
+	GetAllTags() []string
+	MatchesTag(id int, tag string) bool
+
+	ParseDate(value string) (time.Time, error)
+
+	GetTodoSortOrder() int
+	SetTodoSortOrder(sortOrder int)
 
 	IsCompleted(id int) bool
 	HasChildren(id int) bool
@@ -89,7 +143,32 @@ type TaskService interface {
 	SessionDisplayString(id int) string
 
 	SaveNote(contents string)
+	SaveNoteForSession(id int, contents string)
+	ReplanSession(id int) int
+	SessionDetailString(id int) string
 	GetNote() string
+	GetNoteForSession(id int) string
+	GetSessionDate(id int) string
+	StartSession(name string) int
+	StopSession() bool
+
+	GetDeletedTaskIDs() []int
+	TrashString(id int) string
+	RestoreTask(id int) bool
+	PurgeTask(id int) bool
+
+	GetCarryOverTaskIDs() []int
+	GetPreviousSessionDate() string
+	CarryOver(move bool) int
+	SkipCarryOver()
+
+	Undo() bool
+	Redo() bool
+
+	SetWriteErrorHandler(handler func(err error))
+	Save() error
+	Overwrite() error
+	Reload() error
 }
 
 func New(taskService TaskService, userTheme *theme.Theme) *UI {
@@ -108,7 +187,7 @@ func New(taskService TaskService, userTheme *theme.Theme) *UI {
 
 	ui := &UI{
 		taskService:       taskService,
-		todoListSortOrder: Task.SortOrderNone,
+		todoListSortOrder: taskService.GetTodoSortOrder(),
 		theme:             userTheme,
 	}
 
@@ -117,10 +196,14 @@ func New(taskService TaskService, userTheme *theme.Theme) *UI {
 
 	ui.build()
 
+	taskService.SetWriteErrorHandler(ui.writeFailed)
+
 	return ui
 }
 
 func (ui *UI) Run() {
+	go ui.tickTimers()
+
 	if err := ui.app.Run(); err != nil {
 		panic(fmt.Sprintf("Error running application: %v", err))
 	}
@@ -145,8 +228,13 @@ func (ui *UI) build() {
 	ui.addTaskForm = ui.createForm("Add New", addTaskFormName, false, ui.addTaskActionHandler)
 	ui.addChildTaskForm = ui.createForm("Add New Child", addChildTaskFormName, true, ui.addChildTaskActionHandler)
 	ui.editTaskForm = ui.createForm("Edit", editTaskFormName, false, ui.editTaskActionHandler)
+	ui.moveTaskForm = ui.createMoveForm(moveTaskFormName, ui.moveTaskActionHandler)
+	ui.filterForm = ui.createFilterForm(filterFormName, ui.filterActionHandler)
+	ui.sessionForm = ui.createSessionForm(sessionFormName, ui.startSessionActionHandler)
 
 	ui.addNoteForm = ui.createNoteForm(noteFormName, ui.addNoteActionHandler)
+	ui.trashList = ui.createTrashList()
+	ui.searchInput = ui.createSearchInput()
 
 	modal := func(p tview.Primitive, width, height int) tview.Primitive {
 		return tview.NewGrid().
@@ -166,21 +254,57 @@ func (ui *UI) build() {
 					Background(theme.Color(ui.theme.BackgroundFocus))),
 	}
 	ui.sessionList.SetBorder(true).SetTitle(" Sessions ")
+	ui.sessionList.handleInput = ui.sessionInputHandler
+	ui.sessionList.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		if ui.sessionListFocused {
+			ui.showSessionDetails(index)
+		}
+	})
 
 	taskFlex := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(ui.todoList, 0, 3, true).
 		AddItem(ui.completedList, 0, 1, true)
 
-	flex := tview.NewFlex().SetDirection(tview.FlexColumn).
-		AddItem(taskFlex, 0, 3, true).
+	ui.detailPane = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true)
+	ui.detailPane.SetBorder(true).SetTitle(" Details ")
+
+	ui.todoList.SetChangedFunc(ui.showTaskDetails)
+	ui.completedList.SetChangedFunc(ui.showTaskDetails)
+
+	sideFlex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(ui.detailPane, 0, 2, false).
 		AddItem(ui.sessionList, 0, 1, true)
 
+	panes := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(taskFlex, 0, 2, true).
+		AddItem(sideFlex, 0, 1, true)
+
+	ui.statusBar = tview.NewTextView().
+		SetDynamicColors(true)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(panes, 0, 1, true).
+		AddItem(ui.statusBar, 1, 0, false)
+
+	// the search bar sits at the bottom so it doesn't cover the matches
+	searchBar := tview.NewGrid().
+		SetColumns(0).
+		SetRows(0, 3).
+		AddItem(ui.searchInput, 1, 0, 1, 1, 0, 0, true)
+
 	ui.pages.
 		AddPage("list", flex, true, true).
-		AddPage(addTaskFormName, modal(ui.addTaskForm, 100, 9), true, false).
-		AddPage(addChildTaskFormName, modal(ui.addChildTaskForm, 100, 11), true, false).
-		AddPage(editTaskFormName, modal(ui.editTaskForm, 100, 9), true, false).
-		AddPage(noteFormName, modal(ui.addNoteForm, 100, 11), true, false)
+		AddPage(addTaskFormName, modal(ui.addTaskForm, 100, 13+taskBodyHeight), true, false).
+		AddPage(addChildTaskFormName, modal(ui.addChildTaskForm, 100, 15+taskBodyHeight), true, false).
+		AddPage(editTaskFormName, modal(ui.editTaskForm, 100, 13+taskBodyHeight), true, false).
+		AddPage(moveTaskFormName, modal(ui.moveTaskForm, 100, 7), true, false).
+		AddPage(filterFormName, modal(ui.filterForm, 60, 7), true, false).
+		AddPage(sessionFormName, modal(ui.sessionForm, 60, 7), true, false).
+		AddPage(noteFormName, modal(ui.addNoteForm, 100, 11), true, false).
+		AddPage(trashPageName, modal(ui.trashList, 100, 20), true, false).
+		AddPage(searchPageName, searchBar, true, false)
 
 	ui.activeTaskList = ui.todoList
 	ui.refresh()
@@ -191,6 +315,12 @@ func (ui *UI) build() {
 
 	ui.activeTaskList = ui.todoList
 	ui.app.SetRoot(ui.pages, true)
+
+	// the screen is needed to ring the bell when a pomodoro phase ends
+	ui.app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		ui.screen = screen
+		return false
+	})
 }
 
 func (ui *UI) loadTasks() {
@@ -219,6 +349,8 @@ func (ui *UI) parseColors(text string) string {
 	text = strings.ReplaceAll(text, fmt.Sprintf("%s::", Task.PriorityMediumColorKey), fmt.Sprintf("%s::", ui.theme.PriorityMedium))
 	text = strings.ReplaceAll(text, fmt.Sprintf("%s::", Task.PriorityLowColorKey), fmt.Sprintf("%s::", ui.theme.PriorityLow))
 	text = strings.ReplaceAll(text, fmt.Sprintf("%s::", Task.SubTextColorKey), fmt.Sprintf("%s::", ui.theme.SubText))
+	text = strings.ReplaceAll(text, fmt.Sprintf("%s::", Task.OverdueColorKey), fmt.Sprintf("%s::", ui.theme.Overdue))
+	text = strings.ReplaceAll(text, fmt.Sprintf("%s::", Task.DueTodayColorKey), fmt.Sprintf("%s::", ui.theme.DueToday))
 
 	return text
 }
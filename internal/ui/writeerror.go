@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/darwinfroese/scribe/internal/database"
+	"github.com/darwinfroese/scribe/internal/theme"
+)
+
+const (
+	reloadButton    = "Reload"
+	overwriteButton = "Overwrite"
+	retryButton     = "Retry"
+	quitButton      = "Quit"
+)
+
+// writeFailed is called by the task service when a change can't be written.
+// the prompt is queued so that it opens after the current event has been
+// handled, otherwise refreshing the lists would take the focus from it. it's
+// queued from a goroutine since QueueUpdateDraw waits for the event loop.
+func (ui *UI) writeFailed(err error) {
+	go ui.app.QueueUpdateDraw(func() {
+		ui.promptWriteError(err)
+	})
+}
+
+// promptWriteError asks what to do with a change that couldn't be written,
+// if another scribe process changed the database it can be reloaded, losing
+// the change, or overwritten with it. other errors can be retried.
+func (ui *UI) promptWriteError(err error) {
+	if ui.pages.HasPage(writeErrorPageName) {
+		return
+	}
+
+	text := fmt.Sprintf("Your last change couldn't be saved:\n%v", err)
+	buttons := []string{retryButton, quitButton}
+
+	if errors.Is(err, database.ErrModified) {
+		text = "Another scribe process changed the database so your last change couldn't be saved.\n" +
+			"Reload to load their changes and drop yours, or overwrite their changes with yours."
+		buttons = []string{reloadButton, overwriteButton, quitButton}
+	}
+
+	prompt := tview.NewModal().
+		SetText(text).
+		AddButtons(buttons).
+		SetBackgroundColor(theme.Color(ui.theme.Background)).
+		SetTextColor(theme.Color(ui.theme.Text)).
+		SetButtonStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.Text)).Background(theme.Color(ui.theme.Background))).
+		SetButtonActivatedStyle(tcell.StyleDefault.Foreground(theme.Color(ui.theme.TextFocus)).Background(theme.Color(ui.theme.BackgroundFocus)))
+
+	prompt.SetDoneFunc(func(index int, label string) {
+		var err error
+
+		switch label {
+		case reloadButton:
+			err = ui.taskService.Reload()
+			ui.todoListSortOrder = ui.taskService.GetTodoSortOrder()
+		case overwriteButton:
+			err = ui.taskService.Overwrite()
+		case retryButton:
+			err = ui.taskService.Save()
+		default:
+			ui.app.Stop()
+			return
+		}
+
+		ui.pages.RemovePage(writeErrorPageName)
+		ui.hideForm(writeErrorPageName)
+		ui.refresh()
+
+		if err != nil {
+			ui.promptWriteError(err)
+		}
+	})
+
+	ui.pages.AddPage(writeErrorPageName, prompt, true, true)
+	ui.app.SetFocus(prompt)
+
+	ui.formOpen = true
+}
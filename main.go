@@ -4,10 +4,17 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/darwinfroese/scribe/cmd"
+	"github.com/darwinfroese/scribe/cmd/backup"
 	"github.com/darwinfroese/scribe/cmd/report"
 	"github.com/darwinfroese/scribe/cmd/scribe"
+	"github.com/darwinfroese/scribe/cmd/search"
+	"github.com/darwinfroese/scribe/cmd/session"
+	"github.com/darwinfroese/scribe/cmd/stats"
+	"github.com/darwinfroese/scribe/cmd/trash"
 	"github.com/darwinfroese/scribe/internal/config"
 )
 
@@ -22,9 +29,37 @@ func main() {
 	reportCommand.StringVar(&args.Start, "start", "", "the date to start a report from (YYYY-MM-DD format)")
 	reportCommand.StringVar(&args.End, "end", "", "the date to end a report at (YYYY-MM-DD format)")
 	reportCommand.BoolVar(&args.All, "all", false, "generate a report for all session dates")
-	reportCommand.BoolVar(&args.List, "list", false, "list all session dates")
+	reportCommand.BoolVar(&args.List, "list", false, "list all sessions")
+	reportCommand.StringVar(&args.Tag, "tag", "", "only report on tasks with this tag (or whose parent has it)")
+	reportCommand.BoolVar(&args.History, "history", false, "include the history of each task in the report")
+	reportCommand.BoolVar(&args.ByPriority, "by-priority", false, "group each session's tasks by priority")
+	reportCommand.StringVar(&args.Format, "format", "", "the format of the report: text (default), markdown, json, csv or html")
+	reportCommand.StringVar(&args.Template, "template", "", "render the report with a text/template file, or a template named in scribe.toml")
+	reportCommand.StringVar(&args.Period, "period", "", "roll every session in a period up into one report: week, month, YYYY-Www, YYYY-MM or YYYY-MM-DD..YYYY-MM-DD")
 	reportCommand.BoolVar(&args.Global, "global", false, "runs scribe with the global database instead of the local database")
 
+	backupCommand := flag.NewFlagSet("backup", flag.ExitOnError)
+	backupCommand.BoolVar(&args.Global, "global", false, "runs scribe with the global database instead of the local database")
+
+	restoreCommand := flag.NewFlagSet("restore", flag.ExitOnError)
+	restoreCommand.BoolVar(&args.Global, "global", false, "runs scribe with the global database instead of the local database")
+
+	trashCommand := flag.NewFlagSet("trash", flag.ExitOnError)
+	trashCommand.BoolVar(&args.All, "all", false, "purge every task in the trash")
+	trashCommand.BoolVar(&args.Global, "global", false, "runs scribe with the global database instead of the local database")
+
+	searchCommand := flag.NewFlagSet("search", flag.ExitOnError)
+	searchCommand.BoolVar(&args.Global, "global", false, "runs scribe with the global database instead of the local database")
+
+	sessionCommand := flag.NewFlagSet("session", flag.ExitOnError)
+	sessionCommand.BoolVar(&args.Global, "global", false, "runs scribe with the global database instead of the local database")
+
+	statsCommand := flag.NewFlagSet("stats", flag.ExitOnError)
+	statsCommand.StringVar(&args.Start, "start", "", "the date to start the stats from (YYYY-MM-DD format), defaults to the first session")
+	statsCommand.StringVar(&args.End, "end", "", "the date to end the stats at (YYYY-MM-DD format), defaults to today")
+	statsCommand.BoolVar(&args.JSON, "json", false, "write the stats as JSON")
+	statsCommand.BoolVar(&args.Global, "global", false, "runs scribe with the global database instead of the local database")
+
 	cfg := config.Load()
 
 	if len(os.Args) == 1 {
@@ -35,13 +70,114 @@ func main() {
 	switch os.Args[1] {
 	case "report":
 		// here we want to parse everything after 'report'
-		if err := reportCommand.Parse(os.Args[2:]); err != nil {
-			panic(err)
+		positional := parseInterspersed(reportCommand, os.Args[2:])
+
+		if len(positional) > 0 {
+			if positional[0] != "week" && positional[0] != "month" {
+				fmt.Printf("unknown report command \"%s\", expected \"week\" or \"month\"\n", positional[0])
+				os.Exit(1)
+			}
+
+			if args.Period != "" {
+				fmt.Printf("a report can't be for both the %s and --period\n", positional[0])
+				os.Exit(1)
+			}
+
+			args.Period = positional[0]
 		}
-		report.Report(args)
+
+		report.Report(args, cfg)
 		// I just like a line break between the end of output and the command line after
 		// an application exits, this is the easiest way to always apply it.
 		fmt.Println()
+	case "backup":
+		positional := parseInterspersed(backupCommand, os.Args[2:])
+
+		if len(positional) > 0 && positional[0] != "list" {
+			fmt.Printf("unknown backup command \"%s\", expected \"list\"\n", positional[0])
+			os.Exit(1)
+		}
+
+		backup.List(args, cfg)
+		fmt.Println()
+	case "restore":
+		positional := parseInterspersed(restoreCommand, os.Args[2:])
+
+		if len(positional) > 0 {
+			args.Snapshot = positional[0]
+		}
+
+		backup.Restore(args, cfg)
+	case "trash":
+		positional := parseInterspersed(trashCommand, os.Args[2:])
+
+		if len(positional) > 0 {
+			args.Action = positional[0]
+		}
+
+		switch args.Action {
+		case "", "list", "restore", "purge":
+		default:
+			fmt.Printf("unknown trash command \"%s\", expected \"list\", \"restore\" or \"purge\"\n", args.Action)
+			os.Exit(1)
+		}
+
+		for _, arg := range positional[min(len(positional), 1):] {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				fmt.Printf("invalid task id \"%s\"\n", arg)
+				os.Exit(1)
+			}
+
+			args.TaskIDs = append(args.TaskIDs, id)
+		}
+
+		if args.Action == "restore" && len(args.TaskIDs) == 0 {
+			fmt.Println("missing task ids, expected \"trash restore <id>...\"")
+			os.Exit(1)
+		}
+
+		if args.Action == "purge" && len(args.TaskIDs) == 0 && !args.All {
+			fmt.Println("missing task ids, expected \"trash purge <id>...\" or \"trash purge --all\"")
+			os.Exit(1)
+		}
+
+		trash.Trash(args, cfg)
+		fmt.Println()
+	case "search":
+		positional := parseInterspersed(searchCommand, os.Args[2:])
+
+		args.Query = strings.Join(positional, " ")
+		if strings.TrimSpace(args.Query) == "" {
+			fmt.Println("missing search query, expected \"search <query>\"")
+			os.Exit(1)
+		}
+
+		search.Search(args, cfg)
+	case "session":
+		positional := parseInterspersed(sessionCommand, os.Args[2:])
+
+		if len(positional) > 0 {
+			args.Action = positional[0]
+		}
+
+		switch args.Action {
+		case "", "start", "stop":
+		default:
+			fmt.Printf("unknown session command \"%s\", expected \"start\" or \"stop\"\n", args.Action)
+			os.Exit(1)
+		}
+
+		args.Name = strings.Join(positional[min(len(positional), 1):], " ")
+
+		session.Session(args, cfg)
+	case "stats":
+		if err := statsCommand.Parse(os.Args[2:]); err != nil {
+			panic(err)
+		}
+
+		stats.Stats(args, cfg)
+		fmt.Println()
 	default:
 		// we don't have a sub-command here
 		if err := scribeCommand.Parse(os.Args[1:]); err != nil {
@@ -50,3 +186,23 @@ func main() {
 		scribe.Scribe(&args, cfg)
 	}
 }
+
+// parseInterspersed parses flags that come before, between or after the
+// positional arguments and returns the positional arguments in order.
+func parseInterspersed(flags *flag.FlagSet, arguments []string) []string {
+	positional := []string{}
+
+	for {
+		if err := flags.Parse(arguments); err != nil {
+			panic(err)
+		}
+
+		arguments = flags.Args()
+		if len(arguments) == 0 {
+			return positional
+		}
+
+		positional = append(positional, arguments[0])
+		arguments = arguments[1:]
+	}
+}